@@ -0,0 +1,129 @@
+package turing
+
+import (
+	"sort"
+)
+
+// asciiSymbols is the number of symbols looked up in an array instead of a map,
+// programs rarely use anything else.
+const asciiSymbols = 128
+
+// compiledProgram is the Program translated to dense integer state ids and symbol indices,
+// so a step costs a couple of slice lookups instead of two map lookups.
+// It is built once by NewMachine and never modified afterwards.
+type compiledProgram struct {
+	// state names by id
+	states []string
+
+	// state ids by name
+	ids map[string]int
+
+	// symbol index + 1 for the ascii part of the alphabet, 0 means no such symbol
+	ascii [asciiSymbols]int
+
+	// symbol indices for the rest of the alphabet
+	symbols map[rune]int
+
+	// number of symbols in the alphabet
+	width int
+
+	// transitions[state*width+symbol]
+	transitions []compiledTransition
+}
+
+type compiledTransition struct {
+	Transition
+
+	// id of Transition.NextState
+	next int
+
+	// false for the (state, symbol) pairs without transition
+	defined bool
+}
+
+// compile translates program over the given alphabet. The listed states get
+// the first ids in order, even if the program has no transitions for them.
+// Transitions for symbols out of the alphabet are dropped as they can never be applied.
+func compile(program Program, alphabet map[rune]struct{}, states ...string) *compiledProgram {
+	c := &compiledProgram{
+		ids:     make(map[string]int, len(program)+len(states)),
+		symbols: make(map[rune]int),
+	}
+
+	symbols := make([]rune, 0, len(alphabet))
+	for sym := range alphabet {
+		symbols = append(symbols, sym)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	for i, sym := range symbols {
+		if sym >= 0 && sym < asciiSymbols {
+			c.ascii[sym] = i + 1
+		} else {
+			c.symbols[sym] = i
+		}
+	}
+
+	c.width = len(symbols)
+
+	names := make([]string, 0, len(program))
+	for state := range program {
+		names = append(names, state)
+	}
+
+	sort.Strings(names)
+
+	for _, state := range append(states, names...) {
+		c.id(state)
+	}
+
+	for state, transitions := range program {
+		for sym, transition := range transitions {
+			symbol, ok := c.symbol(sym)
+			if !ok {
+				continue
+			}
+
+			c.transitions[c.id(state)*c.width+symbol] = compiledTransition{
+				Transition: transition,
+				next:       c.id(transition.NextState),
+				defined:    true,
+			}
+		}
+	}
+
+	return c
+}
+
+// id returns the id of the state, adding it if the state is met the first time.
+func (c *compiledProgram) id(state string) int {
+	if id, ok := c.ids[state]; ok {
+		return id
+	}
+
+	id := len(c.states)
+	c.ids[state] = id
+	c.states = append(c.states, state)
+	c.transitions = append(c.transitions, make([]compiledTransition, c.width)...)
+
+	return id
+}
+
+// symbol returns the index of sym, false is returned for symbols out of the alphabet.
+func (c *compiledProgram) symbol(sym rune) (int, bool) {
+	if sym >= 0 && sym < asciiSymbols {
+		i := c.ascii[sym]
+
+		return i - 1, i != 0
+	}
+
+	i, ok := c.symbols[sym]
+
+	return i, ok
+}
+
+// transition returns the transition for the state id and symbol index.
+func (c *compiledProgram) transition(state, symbol int) *compiledTransition {
+	return &c.transitions[state*c.width+symbol]
+}
@@ -0,0 +1,143 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMachine_Exec_NonASCIIAlphabet(t *testing.T) {
+	t.Parallel()
+
+	// Replace every 'а' with 'б' and stop at the first blank.
+	program := turing.Program{
+		"Q1": {
+			'а': {NextState: "Q1", Move: turing.Right, Write: 'б'},
+			'б': {NextState: "Q1", Move: turing.Right, Write: 'б'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: ' '},
+		},
+	}
+
+	machine, err := turing.NewMachine(
+		"аб",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		20,
+	)
+	require.NoError(t, err)
+
+	tape, err := machine.Exec(0, map[int]rune{0: 'а', 1: 'б', 2: 'а'})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]rune{0: 'б', 1: 'б', 2: 'б', 3: ' '}, tape)
+
+	_, err = machine.Exec(0, map[int]rune{0: 'а', 1: 'в'})
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+
+	// transitions for symbols out of the alphabet are rejected
+	program["Q1"]['в'] = turing.Transition{NextState: "Q0", Move: turing.Stay, Write: ' '}
+
+	_, err = turing.NewMachine("аб", "Q1", "Q0", program, 20, 20)
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+}
+
+// multiplyProgram calculates the function f(x)=3*x in the unary number system.
+func multiplyProgram() turing.Program {
+	return turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '*'},
+		},
+		"Q2": {
+			' ': {NextState: "Q3", Move: turing.Left, Write: ' '},
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+		},
+		"Q3": {
+			'1': {NextState: "Q4", Move: turing.Left, Write: ' '},
+			'*': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q4": {
+			' ': {NextState: "Q5", Move: turing.Left, Write: '1'},
+			'1': {NextState: "Q4", Move: turing.Left, Write: '1'},
+			'*': {NextState: "Q4", Move: turing.Left, Write: '*'},
+		},
+		"Q5": {
+			' ': {NextState: "Q6", Move: turing.Left, Write: '1'},
+		},
+		"Q6": {
+			' ': {NextState: "Q7", Move: turing.Right, Write: '1'},
+		},
+		"Q7": {
+			' ': {NextState: "Q3", Move: turing.Left, Write: ' '},
+			'1': {NextState: "Q7", Move: turing.Right, Write: '1'},
+			'*': {NextState: "Q7", Move: turing.Right, Write: '*'},
+		},
+	}
+}
+
+// benchmarkInputLength is the length of the unary number multiplied in benchmarks.
+const benchmarkInputLength = 40
+
+func benchmarkInput() map[int]rune {
+	input := make(map[int]rune, benchmarkInputLength)
+	for i := 0; i < benchmarkInputLength; i++ {
+		input[i] = '1'
+	}
+
+	return input
+}
+
+func BenchmarkMachine_Exec(b *testing.B) {
+	machine, err := turing.NewMachine(
+		"1*",
+		"Q1",
+		"Q0",
+		multiplyProgram(),
+		100000,
+		0,
+	)
+	require.NoError(b, err)
+
+	input := benchmarkInput()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := machine.Exec(0, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkProgram_MapLookup runs the same program with the step loop looking
+// transitions up in the Program maps, as the machine did before compiling programs.
+func BenchmarkProgram_MapLookup(b *testing.B) {
+	program := multiplyProgram()
+	alphabet := map[rune]struct{}{'1': {}, '*': {}, ' ': {}}
+	input := benchmarkInput()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tape := turing.TapeFromMap(input)
+		state, carriage := "Q1", 0
+
+		for state != "Q0" {
+			sym := tape.Read(carriage)
+			if _, ok := alphabet[sym]; !ok {
+				b.Fatal(turing.ErrUnexpectedSymbol)
+			}
+
+			transition, ok := program[state][sym]
+			if !ok {
+				b.Fatal(turing.ErrTransitionNotFound)
+			}
+
+			tape.Write(carriage, transition.Write)
+			carriage += int(transition.Move)
+			state = transition.NextState
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrLinkNotFound is returned when a link refers to a link which does not exist.
+	ErrLinkNotFound = errors.New("link not found")
+
+	// ErrDuplicateLink is returned when several links have the same name.
+	ErrDuplicateLink = errors.New("duplicate link")
+)
+
+// Link is a program chained with others by Compose.
+type Link struct {
+	// Name namespaces the states of the program: state Q1 of the link "add" becomes "add.Q1".
+	Name string
+
+	Program Program
+
+	// Start and Terminal are the start and the terminal states of the program.
+	Start, Terminal string
+
+	// Next is the name of the link started when the program reaches its terminal state.
+	// Empty Next continues with the following link, or halts after the last one.
+	// The terminal state of the composed program is a valid Next halting the machine.
+	Next string
+
+	// Branch overrides Next for the listed symbols under the carriage
+	// when the program reaches its terminal state.
+	Branch map[rune]string
+}
+
+// namespaced returns the name of the link state in the composed program.
+func namespaced(namespace, state string) string {
+	return namespace + "." + state
+}
+
+// Compose links programs into one: the terminal state of each link flows into the start state
+// of the next one. The states of each link are namespaced with its name, so the programs
+// may use the same state names. The composed program is validated over the alphabet,
+// which must include the blank symbol, and returned with its start state,
+// which is the start state of the first link.
+//
+// Links without Branch are joined directly. Branching takes an extra step
+// with the carriage staying in place to read the symbol.
+func Compose(alphabet map[rune]struct{}, terminal string, links ...Link) (Program, string, error) {
+	if terminal == "" {
+		return nil, "", ErrTerminalStateEmpty
+	}
+
+	if len(links) == 0 {
+		return nil, "", fmt.Errorf("%w: nothing to compose", ErrLinkNotFound)
+	}
+
+	starts := map[string]string{terminal: terminal}
+
+	for _, link := range links {
+		if link.Start == "" {
+			return nil, "", fmt.Errorf("%w: link %q", ErrStartStateEmpty, link.Name)
+		}
+
+		if link.Terminal == "" {
+			return nil, "", fmt.Errorf("%w: link %q", ErrTerminalStateEmpty, link.Name)
+		}
+
+		if _, ok := starts[link.Name]; ok {
+			return nil, "", fmt.Errorf("%w: %q", ErrDuplicateLink, link.Name)
+		}
+
+		starts[link.Name] = namespaced(link.Name, link.Start)
+	}
+
+	composed := make(Program)
+
+	for i, link := range links {
+		next := link.Next
+		if next == "" {
+			next = terminal
+			if i+1 < len(links) {
+				next = links[i+1].Name
+			}
+		}
+
+		target, ok := starts[next]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: %q for link %q", ErrLinkNotFound, next, link.Name)
+		}
+
+		exit := target
+		if len(link.Branch) > 0 {
+			exit = namespaced(link.Name, link.Terminal)
+
+			glue, err := branch(alphabet, link, starts, target)
+			if err != nil {
+				return nil, "", err
+			}
+
+			composed[exit] = glue
+		}
+
+		program := make(Program, len(link.Program))
+		for state, transitions := range link.Program {
+			// transitions of the terminal state are never applied
+			if state != link.Terminal {
+				program[state] = transitions
+			}
+		}
+
+		renamed := renameStates(program, func(state string) string {
+			if state == link.Terminal {
+				return exit
+			}
+
+			return namespaced(link.Name, state)
+		})
+
+		for state, transitions := range renamed {
+			composed[state] = transitions
+		}
+	}
+
+	start := starts[links[0].Name]
+	if err := composed.ValidateStart(alphabet, start, terminal); err != nil {
+		return nil, "", err
+	}
+
+	return composed, start, nil
+}
+
+// branch returns the transitions of the state reading the symbol at the end of the link.
+func branch(alphabet map[rune]struct{}, link Link, starts map[string]string, next string) (map[rune]Transition, error) {
+	glue := make(map[rune]Transition, len(alphabet))
+
+	for sym := range alphabet {
+		glue[sym] = Transition{NextState: next, Move: Stay, Write: sym}
+	}
+
+	symbols := make([]rune, 0, len(link.Branch))
+	for sym := range link.Branch {
+		symbols = append(symbols, sym)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	for _, sym := range symbols {
+		if _, ok := alphabet[sym]; !ok {
+			return nil, fmt.Errorf("%w: %q in branch of link %q", ErrUnexpectedSymbol, sym, link.Name)
+		}
+
+		target, ok := starts[link.Branch[sym]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q for link %q", ErrLinkNotFound, link.Branch[sym], link.Name)
+		}
+
+		glue[sym] = Transition{NextState: target, Move: Stay, Write: sym}
+	}
+
+	return glue, nil
+}
+
+// renameStates returns a copy of program with every state, including the next states
+// of the transitions, renamed.
+func renameStates(program Program, rename func(string) string) Program {
+	renamed := make(Program, len(program))
+
+	for state, transitions := range program {
+		renamedTransitions := make(map[rune]Transition, len(transitions))
+
+		for sym, transition := range transitions {
+			transition.NextState = rename(transition.NextState)
+			renamedTransitions[sym] = transition
+		}
+
+		renamed[rename(state)] = renamedTransitions
+	}
+
+	return renamed
+}
@@ -0,0 +1,199 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	// increment and get back to the first symbol, both programs use the same state names
+	program, start, err := turing.Compose(
+		alphabet,
+		"Q0",
+		turing.Link{
+			Name:     "inc",
+			Start:    "Q1",
+			Terminal: "Q0",
+			Program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+					' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+				},
+			},
+		},
+		turing.Link{
+			Name:     "back",
+			Start:    "Q1",
+			Terminal: "Q0",
+			Program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q1", Move: turing.Left, Write: '1'},
+					' ': {NextState: "Q0", Move: turing.Right, Write: ' '},
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "inc.Q1", start)
+	assert.Equal(t, turing.Transition{NextState: "back.Q1", Move: turing.Stay, Write: '1'}, program["inc.Q1"][' '])
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithStartState(start),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(20),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("11", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "111", result.Tape.String())
+	assert.Equal(t, 0, machine.Snapshot().Carriage)
+}
+
+func TestCompose_Branch(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, 'a': {}, 'b': {}, ' ': {}}
+
+	mark := func(sym, write rune) turing.Program {
+		return turing.Program{"Q1": {sym: {NextState: "Q0", Move: turing.Stay, Write: write}}}
+	}
+
+	program, start, err := turing.Compose(
+		alphabet,
+		"H",
+		turing.Link{
+			Name:     "read",
+			Start:    "Q1",
+			Terminal: "Q0",
+			Program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q0", Move: turing.Right, Write: '1'},
+					' ': {NextState: "Q0", Move: turing.Right, Write: ' '},
+				},
+			},
+			Branch: map[rune]string{'1': "one", ' ': "blank", 'a': "H"},
+		},
+		turing.Link{Name: "one", Start: "Q1", Terminal: "Q0", Program: mark('1', 'a'), Next: "H"},
+		turing.Link{Name: "blank", Start: "Q1", Terminal: "Q0", Program: mark(' ', 'b')},
+	)
+	require.NoError(t, err)
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1ab"),
+		turing.WithStartState(start),
+		turing.WithTerminalState("H"),
+		turing.WithMaxTapeLength(20),
+	)
+	require.NoError(t, err)
+
+	tt := []struct {
+		input string
+		want  string
+	}{
+		{input: "11", want: "1a"},
+		{input: "1", want: "1b"},
+		{input: "1a", want: "1a"},
+	}
+
+	for _, tc := range tt {
+		result, err := machine.Run(0, turing.TapeFromString(tc.input, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, result.Tape.String(), tc.input)
+	}
+}
+
+func TestCompose_Errors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+	program := turing.Program{"Q1": {'1': {NextState: "Q0", Move: turing.Right, Write: '1'}}}
+
+	link := func(name string) turing.Link {
+		return turing.Link{Name: name, Program: program, Start: "Q1", Terminal: "Q0"}
+	}
+
+	withNext := link("a")
+	withNext.Next = "c"
+
+	withBranch := link("a")
+	withBranch.Branch = map[rune]string{'1': "c"}
+
+	withSymbol := link("a")
+	withSymbol.Branch = map[rune]string{'x': "b"}
+
+	noStart := link("a")
+	noStart.Start = ""
+
+	missingStart := link("a")
+	missingStart.Start = "Q5"
+
+	tt := []struct {
+		name  string
+		links []turing.Link
+		err   error
+	}{
+		{name: "no links", err: turing.ErrLinkNotFound},
+		{name: "duplicate", links: []turing.Link{link("a"), link("a")}, err: turing.ErrDuplicateLink},
+		{name: "unknown next", links: []turing.Link{withNext, link("b")}, err: turing.ErrLinkNotFound},
+		{name: "unknown branch", links: []turing.Link{withBranch, link("b")}, err: turing.ErrLinkNotFound},
+		{name: "branch symbol", links: []turing.Link{withSymbol, link("b")}, err: turing.ErrUnexpectedSymbol},
+		{name: "empty start", links: []turing.Link{noStart}, err: turing.ErrStartStateEmpty},
+		{name: "missing start", links: []turing.Link{missingStart}, err: turing.ErrStateNotFound},
+		{name: "missing next start", links: []turing.Link{link("b"), missingStart}, err: turing.ErrStateNotFound},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := turing.Compose(alphabet, "H", tc.links...)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestCompose_Loop(t *testing.T) {
+	t.Parallel()
+
+	// erases one symbol at a time until the blank
+	program, start, err := turing.Compose(
+		map[rune]struct{}{'1': {}, ' ': {}},
+		"H",
+		turing.Link{
+			Name:     "erase",
+			Start:    "Q1",
+			Terminal: "Q0",
+			Program: turing.Program{
+				"Q1": {'1': {NextState: "Q0", Move: turing.Right, Write: ' '}},
+			},
+			Next:   "erase",
+			Branch: map[rune]string{' ': "H"},
+		},
+	)
+	require.NoError(t, err)
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithStartState(start),
+		turing.WithTerminalState("H"),
+		turing.WithMaxTapeLength(20),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("111", 0))
+	require.NoError(t, err)
+	assert.Empty(t, result.Tape.String())
+	assert.Equal(t, uint(6), result.Steps)
+}
@@ -0,0 +1,362 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LoopDetection selects the infinite loop checks made during the execution in addition
+// to the check of a transition staying in the same state and cell without changes,
+// which is always made.
+type LoopDetection uint
+
+// Available infinite loop checks, they can be combined with |.
+const (
+	// DetectCycles detects a configuration (state, carriage position and tape)
+	// repeating itself. It costs a hash update per step and a tape copy each
+	// time the number of steps doubles.
+	DetectCycles LoopDetection = 1 << iota
+
+	// DetectTranslatedCycles detects the machine running off to infinity: the same
+	// state and the same tape around the carriage repeating shifted by a fixed offset.
+	// It costs a tape copy each time the carriage reaches a new cell at the tape edge.
+	DetectTranslatedCycles
+)
+
+// ErrTranslatedCycle is returned when the machine repeats the same configuration
+// shifted along the tape, so it will never halt and goes along the tape infinitely.
+var ErrTranslatedCycle = errors.New("translated cycle")
+
+// CycleError is the error wrapped by ExecError when a configuration repeats itself.
+// It unwraps to ErrInfiniteLoop, or to ErrTranslatedCycle if the configuration
+// repeats shifted by Drift cells.
+type CycleError struct {
+	// Start is the step at which the cycle began, the configuration after Start
+	// steps repeats every Period steps.
+	Start uint
+
+	// Period is the length of the cycle in steps.
+	Period uint
+
+	// Drift is the number of cells the configuration is shifted by each Period steps,
+	// positive to the right. It is zero for cycles in place.
+	Drift int
+}
+
+func (e *CycleError) Error() string {
+	if e.Drift != 0 {
+		return fmt.Sprintf("%v: period of %d steps, drift of %d cells from step %d", ErrTranslatedCycle, e.Period, e.Drift, e.Start)
+	}
+
+	return fmt.Sprintf("%v: cycle of %d steps from step %d", ErrInfiniteLoop, e.Period, e.Start)
+}
+
+func (e *CycleError) Unwrap() error {
+	if e.Drift != 0 {
+		return ErrTranslatedCycle
+	}
+
+	return ErrInfiniteLoop
+}
+
+// configuration is a copy of the machine configuration kept by the cycle detector.
+type configuration struct {
+	state    int
+	carriage int
+	tape     *Tape
+	hash     uint64
+}
+
+// cycleDetector finds repeating configurations with Brent's algorithm:
+// the configuration is saved each time the number of steps since the last save
+// reaches a power of two, and every configuration is compared with the saved one.
+// The tape is hashed incrementally, so comparing costs O(1) until hashes match.
+type cycleDetector struct {
+	// the machine configuration after Reset, used to find where the cycle began
+	initial configuration
+
+	// the last saved configuration
+	saved configuration
+
+	// hash of the current tape, blank cells do not change it
+	tapeHash uint64
+
+	blank rune
+
+	// Brent's algorithm counters: steps since the last save and the next save distance
+	lambda, power uint
+}
+
+func newCycleDetector(m *Machine) *cycleDetector {
+	d := &cycleDetector{blank: m.blank, power: 1}
+
+	for pos, sym := range m.tape.Map() {
+		d.tapeHash ^= d.cellHash(pos, sym)
+	}
+
+	d.initial = d.configuration(m)
+	d.saved = d.initial
+
+	return d
+}
+
+// clone returns an independent copy of the detector, nil stays nil.
+func (d *cycleDetector) clone() *cycleDetector {
+	if d == nil {
+		return nil
+	}
+
+	c := *d
+
+	return &c
+}
+
+// cellHash returns the hash of a single cell, the blank symbol hashes to zero,
+// so the tape hash does not depend on how far the tape was grown.
+func (d *cycleDetector) cellHash(pos int, sym rune) uint64 {
+	if sym == d.blank {
+		return 0
+	}
+
+	return mix(uint64(pos)*0x9e3779b97f4a7c15 ^ uint64(sym)) //nolint:gosec
+}
+
+// write updates the tape hash after sym was replaced with write at pos.
+func (d *cycleDetector) write(pos int, sym, write rune) {
+	d.tapeHash ^= d.cellHash(pos, sym) ^ d.cellHash(pos, write)
+}
+
+func (d *cycleDetector) hash(state, carriage int) uint64 {
+	return d.tapeHash ^ mix(uint64(state)<<32^uint64(carriage)) //nolint:gosec
+}
+
+func (d *cycleDetector) configuration(m *Machine) configuration {
+	return configuration{
+		state:    m.state,
+		carriage: m.carriage,
+		tape:     m.tape.Clone(),
+		hash:     d.hash(m.state, m.carriage),
+	}
+}
+
+// check is called after every step, it returns the cycle if the current
+// configuration repeats the saved one.
+func (d *cycleDetector) check(m *Machine) *CycleError {
+	d.lambda++
+
+	hash := d.hash(m.state, m.carriage)
+
+	if hash == d.saved.hash && d.saved.state == m.state && d.saved.carriage == m.carriage && d.saved.tape.Equal(m.tape) {
+		return &CycleError{Start: d.start(m, d.lambda), Period: d.lambda}
+	}
+
+	if d.lambda == d.power {
+		d.saved = d.configuration(m)
+		d.power *= 2
+		d.lambda = 0
+	}
+
+	return nil
+}
+
+// start finds the first step of the cycle with the given period by running two
+// replicas of the machine from the initial configuration period steps apart
+// until their configurations meet.
+func (d *cycleDetector) start(m *Machine, period uint) uint {
+	replica := func() *Machine {
+		r := *m
+		r.tracer = nil
+		r.cycles = nil
+		r.translations = nil
+		r.stuck = false
+		r.steps = 0
+		r.state = d.initial.state
+		r.carriage = d.initial.carriage
+		r.tape = d.initial.tape.Clone()
+
+		return &r
+	}
+
+	slow, fast := replica(), replica()
+
+	// both replicas follow the path the machine has already made without errors
+	for i := uint(0); i < period; i++ {
+		_, _ = fast.step()
+	}
+
+	var start uint
+	for slow.state != fast.state || slow.carriage != fast.carriage || !slow.tape.Equal(fast.tape) {
+		_, _ = slow.step()
+		_, _ = fast.step()
+		start++
+	}
+
+	return start
+}
+
+// mix is the splitmix64 finalizer spreading the bits of x over the whole hash.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// translationDetector finds translated cycles. A record is made each time the carriage
+// reaches a new cell beyond an edge of the used tape, all the cells farther are blank then.
+// Two records at the same edge in the same state form a translated cycle if the tape
+// the machine has visited between them is the same shifted by the distance between records:
+// the machine then repeats the same steps shifted again and again.
+type translationDetector struct {
+	// records at the left and the right edges
+	edges [2]edge
+}
+
+// Indices of translationDetector.edges.
+const (
+	leftEdge = iota
+	rightEdge
+)
+
+type edge struct {
+	// direction from the tape towards the edge, -1 for the left one and 1 for the right one
+	dir int
+
+	// records made at the edge, oldest first
+	records []edgeRecord
+
+	// index of the last record made in each state
+	last map[int]int
+}
+
+type edgeRecord struct {
+	step     uint
+	state    int
+	carriage int
+
+	// the farthest position from the edge the carriage reached since this record
+	// until the next one
+	reach int
+
+	// tape at the moment of the record, only kept for the last record of each state
+	tape *Tape
+}
+
+func newTranslationDetector() *translationDetector {
+	return &translationDetector{
+		edges: [2]edge{
+			leftEdge:  {dir: -1, last: make(map[int]int)},
+			rightEdge: {dir: 1, last: make(map[int]int)},
+		},
+	}
+}
+
+// clone returns an independent copy of the detector, nil stays nil.
+func (d *translationDetector) clone() *translationDetector {
+	if d == nil {
+		return nil
+	}
+
+	c := newTranslationDetector()
+
+	for i, e := range d.edges {
+		c.edges[i].records = append([]edgeRecord(nil), e.records...)
+		for state, record := range e.last {
+			c.edges[i].last[state] = record
+		}
+	}
+
+	return c
+}
+
+// check is called after every step, it returns the translated cycle if the
+// carriage has reached a new cell repeating a previous record.
+// The left edge is not watched on one-way tapes: the machine drifting to the left
+// reaches cell 0 and runs differently there, so it does not repeat forever.
+func (d *translationDetector) check(m *Machine) *CycleError {
+	lo, hi := m.tape.bounds()
+
+	for i := range d.edges {
+		e := &d.edges[i]
+
+		if e.dir < 0 && m.tapeMode != TwoWay {
+			continue
+		}
+
+		// the carriage moving away from the edge
+		if n := len(e.records); n > 0 && e.dir*(e.records[n-1].reach-m.carriage) > 0 {
+			e.records[n-1].reach = m.carriage
+		}
+
+		if (e.dir < 0 && m.carriage >= lo) || (e.dir > 0 && m.carriage < hi) {
+			continue
+		}
+
+		if cycle := e.record(m); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// record adds the current configuration to the edge records and compares it
+// with the previous record in the same state.
+func (e *edge) record(m *Machine) *CycleError {
+	current := edgeRecord{
+		step:     m.steps,
+		state:    m.state,
+		carriage: m.carriage,
+		reach:    m.carriage,
+		tape:     m.tape.Clone(),
+	}
+
+	if i, ok := e.last[m.state]; ok {
+		previous := e.records[i]
+
+		// the farthest position from the edge visited between the records
+		reach := previous.reach
+		for _, r := range e.records[i:] {
+			if e.dir*(reach-r.reach) > 0 {
+				reach = r.reach
+			}
+		}
+
+		drift := current.carriage - previous.carriage
+
+		if sameSegment(previous.tape, reach, previous.carriage, m.tape, drift) {
+			return &CycleError{
+				Start:  previous.step,
+				Period: current.step - previous.step,
+				Drift:  drift,
+			}
+		}
+
+		// only the last record of each state keeps the tape
+		e.records[i].tape = nil
+	}
+
+	e.last[m.state] = len(e.records)
+	e.records = append(e.records, current)
+
+	return nil
+}
+
+// sameSegment reports whether the cells between from and to inclusive on the tape a
+// are the same as the cells shifted by drift on the tape b.
+func sameSegment(a *Tape, from, to int, b *Tape, drift int) bool {
+	if from > to {
+		from, to = to, from
+	}
+
+	for pos := from; pos <= to; pos++ {
+		if a.Read(pos) != b.Read(pos+drift) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,261 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMachine_Exec_DetectCycles(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		program turing.Program
+		input   string
+
+		start  uint
+		period uint
+	}{
+		{
+			name: "bounce between two cells",
+			program: turing.Program{
+				"Q1": {'1': {NextState: "Q2", Move: turing.Right, Write: '1'}},
+				"Q2": {' ': {NextState: "Q1", Move: turing.Left, Write: ' '}},
+			},
+			input:  "1",
+			start:  0,
+			period: 2,
+		},
+		{
+			name: "bounce after going to the end of input",
+			program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+					' ': {NextState: "Q2", Move: turing.Left, Write: ' '},
+				},
+				"Q2": {'1': {NextState: "Q3", Move: turing.Right, Write: '1'}},
+				"Q3": {' ': {NextState: "Q2", Move: turing.Left, Write: ' '}},
+			},
+			input:  "111",
+			start:  4,
+			period: 2,
+		},
+		{
+			name: "cycle through several states changing the tape",
+			program: turing.Program{
+				"Q1": {'1': {NextState: "Q2", Move: turing.Right, Write: 'x'}},
+				"Q2": {' ': {NextState: "Q3", Move: turing.Left, Write: ' '}},
+				"Q3": {'x': {NextState: "Q1", Move: turing.Stay, Write: '1'}},
+			},
+			input:  "1",
+			start:  0,
+			period: 3,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			machine, err := turing.New(
+				tc.program,
+				turing.WithAlphabet("1x"),
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(100),
+				turing.WithMaxSteps(1000),
+				turing.WithLoopDetection(turing.DetectCycles),
+			)
+			require.NoError(t, err)
+
+			_, err = machine.ExecTape(0, turing.TapeFromString(tc.input, 0))
+			require.ErrorIs(t, err, turing.ErrInfiniteLoop)
+
+			var cycle *turing.CycleError
+			require.ErrorAs(t, err, &cycle)
+			assert.Equal(t, tc.start, cycle.Start)
+			assert.Equal(t, tc.period, cycle.Period)
+
+			// without detection the machine runs until the steps limit
+			machine, err = turing.New(
+				tc.program,
+				turing.WithAlphabet("1x"),
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(100),
+				turing.WithMaxSteps(1000),
+			)
+			require.NoError(t, err)
+
+			_, err = machine.ExecTape(0, turing.TapeFromString(tc.input, 0))
+			require.ErrorIs(t, err, turing.ErrStepsExceeded)
+		})
+	}
+}
+
+func TestMachine_Exec_DetectCycles_Halting(t *testing.T) {
+	t.Parallel()
+
+	machine, err := turing.New(
+		multiplyProgram(),
+		turing.WithAlphabet("1*"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(1000),
+		turing.WithLoopDetection(turing.DetectCycles),
+	)
+	require.NoError(t, err)
+
+	tape, err := machine.ExecTape(0, turing.TapeFromString("111", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "1111111", tape.String())
+}
+
+func TestMachine_Exec_DetectTranslatedCycles(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		program turing.Program
+		input   string
+
+		start  uint
+		period uint
+		drift  int
+	}{
+		{
+			name: "go right writing ones",
+			program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+					' ': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				},
+			},
+			input:  "111",
+			start:  3,
+			period: 1,
+			drift:  1,
+		},
+		{
+			name: "go left writing a pattern",
+			program: turing.Program{
+				"Q1": {' ': {NextState: "Q2", Move: turing.Left, Write: 'a'}},
+				"Q2": {' ': {NextState: "Q1", Move: turing.Left, Write: 'b'}},
+			},
+			input:  "",
+			start:  1,
+			period: 2,
+			drift:  -2,
+		},
+		{
+			name: "zigzag to the right",
+			program: turing.Program{
+				"Q1": {' ': {NextState: "Q2", Move: turing.Right, Write: 'a'}},
+				"Q2": {' ': {NextState: "Q3", Move: turing.Left, Write: 'b'}},
+				"Q3": {'a': {NextState: "Q4", Move: turing.Right, Write: 'a'}},
+				"Q4": {'b': {NextState: "Q1", Move: turing.Right, Write: 'b'}},
+			},
+			input:  "",
+			start:  1,
+			period: 4,
+			drift:  2,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			machine, err := turing.New(
+				tc.program,
+				turing.WithAlphabet("1ab"),
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(100),
+				turing.WithLoopDetection(turing.DetectCycles|turing.DetectTranslatedCycles),
+			)
+			require.NoError(t, err)
+
+			_, err = machine.ExecTape(0, turing.TapeFromString(tc.input, 0))
+			require.ErrorIs(t, err, turing.ErrTranslatedCycle)
+			require.NotErrorIs(t, err, turing.ErrTapeOver)
+
+			var cycle *turing.CycleError
+			require.ErrorAs(t, err, &cycle)
+			assert.Equal(t, tc.start, cycle.Start)
+			assert.Equal(t, tc.period, cycle.Period)
+			assert.Equal(t, tc.drift, cycle.Drift)
+		})
+	}
+}
+
+func TestMachine_Exec_DetectTranslatedCycles_GrowingSweep(t *testing.T) {
+	t.Parallel()
+
+	// Sweep over the ones adding one at each end, the sweeps get longer,
+	// so it is not a translated cycle.
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q2", Move: turing.Left, Write: '1'},
+		},
+		"Q2": {
+			'1': {NextState: "Q2", Move: turing.Left, Write: '1'},
+			' ': {NextState: "Q1", Move: turing.Right, Write: '1'},
+		},
+	}
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(50),
+		turing.WithLoopDetection(turing.DetectCycles|turing.DetectTranslatedCycles),
+	)
+	require.NoError(t, err)
+
+	_, err = machine.ExecTape(0, turing.TapeFromString("1", 0))
+	require.ErrorIs(t, err, turing.ErrTapeOver)
+}
+
+func TestMachine_Exec_DetectTranslatedCycles_OneWayTape(t *testing.T) {
+	t.Parallel()
+
+	// Go left writing x, the one-way tape stops the machine at cell 0.
+	program := turing.Program{
+		"Q1": {
+			' ': {NextState: "Q1", Move: turing.Left, Write: 'x'},
+			'x': {NextState: "Q0", Move: turing.Stay, Write: 'x'},
+		},
+	}
+
+	newMachine := func(mode turing.TapeMode) *turing.Machine {
+		machine, err := turing.New(
+			program,
+			turing.WithAlphabet("x"),
+			turing.WithStartState("Q1"),
+			turing.WithTerminalState("Q0"),
+			turing.WithMaxTapeLength(100),
+			turing.WithTapeMode(mode),
+			turing.WithLoopDetection(turing.DetectCycles|turing.DetectTranslatedCycles),
+		)
+		require.NoError(t, err)
+
+		return machine
+	}
+
+	_, err := newMachine(turing.TwoWay).Exec(5, map[int]rune{})
+	require.ErrorIs(t, err, turing.ErrTranslatedCycle)
+
+	tape, err := newMachine(turing.OneWayStay).Exec(5, map[int]rune{})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]rune{0: 'x', 1: 'x', 2: 'x', 3: 'x', 4: 'x', 5: 'x'}, tape)
+
+	_, err = newMachine(turing.OneWayError).Exec(5, map[int]rune{})
+	require.ErrorIs(t, err, turing.ErrLeftEdge)
+	require.NotErrorIs(t, err, turing.ErrTranslatedCycle)
+}
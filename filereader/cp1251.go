@@ -0,0 +1,83 @@
+package filereader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedCharacter is returned when a character cannot be encoded in Windows-1251,
+// the encoding of .tur files.
+var ErrUnsupportedCharacter = errors.New("unsupported character")
+
+// cp1251High maps the bytes 0x80-0xBF of Windows-1251 to runes, 0x98 is not used.
+// The bytes 0xC0-0xFF are the letters А-я in the Unicode order.
+var cp1251High = [64]rune{
+	'Ђ', 'Ѓ', '‚', 'ѓ', '„', '…', '†', '‡', '€', '‰', 'Љ', '‹', 'Њ', 'Ќ', 'Ћ', 'Џ',
+	'ђ', '‘', '’', '“', '”', '•', '–', '—', utf8.RuneError, '™', 'љ', '›', 'њ', 'ќ', 'ћ', 'џ',
+	'\u00a0', 'Ў', 'ў', 'Ј', '¤', 'Ґ', '¦', '§', 'Ё', '©', 'Є', '«', '¬', '\u00ad', '®', 'Ї',
+	'°', '±', 'І', 'і', 'ґ', 'µ', '¶', '·', 'ё', '№', 'є', '»', 'ј', 'Ѕ', 'ѕ', 'ї',
+}
+
+// cp1251Bytes maps the runes of cp1251High back to the bytes.
+var cp1251Bytes = func() map[rune]byte {
+	bytes := make(map[rune]byte, len(cp1251High))
+	for i, r := range cp1251High {
+		if r != utf8.RuneError {
+			bytes[r] = byte(0x80 + i)
+		}
+	}
+
+	return bytes
+}()
+
+// encodeCP1251 encodes s in Windows-1251.
+func encodeCP1251(s string) ([]byte, error) {
+	encoded := make([]byte, 0, len(s))
+
+	for _, r := range s {
+		b, ok := encodeRuneCP1251(r)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedCharacter, r)
+		}
+
+		encoded = append(encoded, b)
+	}
+
+	return encoded, nil
+}
+
+// encodeRuneCP1251 returns the Windows-1251 byte of r and whether r can be encoded.
+func encodeRuneCP1251(r rune) (byte, bool) {
+	switch {
+	case r < 0x80:
+		return byte(r), true
+	case r >= 'А' && r <= 'я':
+		return byte(0xC0 + r - 'А'), true
+	default:
+		b, ok := cp1251Bytes[r]
+
+		return b, ok
+	}
+}
+
+// decodeCP1251 decodes Windows-1251 text, the unused byte 0x98 becomes utf8.RuneError.
+func decodeCP1251(b []byte) string {
+	var sb strings.Builder
+
+	sb.Grow(len(b))
+
+	for _, c := range b {
+		switch {
+		case c < 0x80:
+			sb.WriteByte(c)
+		case c >= 0xC0:
+			sb.WriteRune('А' + rune(c-0xC0))
+		default:
+			sb.WriteRune(cp1251High[c-0x80])
+		}
+	}
+
+	return sb.String()
+}
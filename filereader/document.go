@@ -0,0 +1,374 @@
+package filereader
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/asphodex/go-turing"
+)
+
+// Document is the content of a .tur file.
+// Files mangled after the simulator saved them, like ones converted to another encoding,
+// cannot be read as documents: their comments and saved tape are lost, ReadCtx reads
+// their state table only.
+type Document struct {
+	// Comment is the program comment, the description of the task.
+	Comment string
+
+	// Program names the states Q1, Q2... after the columns of the state table,
+	// Q0 is the halting state.
+	Program turing.Program
+
+	// Start and Terminal are the start and the halting states of the program,
+	// StartState and TerminalState for read documents. The simulator always starts in Q1
+	// and halts in Q0, so Write fails with ErrInvalidState for other states, empty ones
+	// stand for these.
+	Start, Terminal string
+
+	// Alphabet lists the symbols in the order of the table rows.
+	// The blank symbol is always the last row.
+	Alphabet []rune
+
+	// Blank is the symbol of the blank cells in Program and Alphabet, the file itself
+	// has the blank row and '_' in transitions for it. A zero Blank stands for
+	// turing.DefaultBlank unless BlankSet is true, so that zero can be the blank symbol too.
+	// Read documents have the symbol of WithBlank, turing.DefaultBlank by default,
+	// and BlankSet set.
+	Blank    rune
+	BlankSet bool
+
+	// Notes is the state table comment.
+	Notes string
+
+	// StateComments are the comments of the states found in Notes on lines
+	// starting with the state name, like "Q2 - skip the first number".
+	// Write ignores them and writes Notes.
+	StateComments map[string]string
+
+	// Tape is the saved tape, nil if the file has none.
+	Tape *SavedTape
+}
+
+// blankSymbol returns the symbol of the blank cells, see Blank.
+func (doc *Document) blankSymbol() rune {
+	if doc.Blank == 0 && !doc.BlankSet {
+		return turing.DefaultBlank
+	}
+
+	return doc.Blank
+}
+
+// SavedTape is the tape saved in a .tur file together with the carriage position.
+type SavedTape struct {
+	// Cells are the symbols of the saved cells, blank cells are spaces.
+	Cells string
+
+	// Start is the position of the first saved cell.
+	Start int
+
+	// Carriage is the position of the carriage.
+	Carriage int
+}
+
+// Tape returns the saved cells on a tape with the given blank symbol.
+func (st *SavedTape) Tape(blank rune) *turing.Tape {
+	tape := turing.NewTape(blank)
+
+	for i, sym := range []rune(st.Cells) {
+		if sym != ' ' {
+			tape.Write(st.Start+i, sym)
+		}
+	}
+
+	return tape
+}
+
+// ReadDocumentFileCtx reads all the sections of the .tur file from given filepath.
+func ReadDocumentFileCtx(ctx context.Context, filePath string, opts ...Option) (*Document, error) {
+	file, err := openFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return ReadDocumentCtx(ctx, file, opts...)
+}
+
+// ReadDocumentCtx reads all the sections of a .tur file from the given io.Reader.
+// Files ending in the middle of a section fail with ErrTruncatedFile, files framed
+// in another way fail with ErrCorruptFile, ErrParseTable or ErrParseTransition.
+// Data which does not start like a .tur file, like a file converted to another encoding
+// and saved back as text, fails with ErrCorruptFile, ReadCtx still reads its state table.
+func ReadDocumentCtx(ctx context.Context, r io.Reader, opts ...Option) (*Document, error) {
+	c := newConfig(opts)
+
+	data, err := readAll(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !framed(data) {
+		return nil, fmt.Errorf("%w: data is not framed as a .tur file", ErrCorruptFile)
+	}
+
+	return decodeDocument(ctx, data, c.blank)
+}
+
+// readAll reads the whole file from r.
+func readAll(ctx context.Context, r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read program: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err() //nolint:wrapcheck
+	}
+
+	return data, nil
+}
+
+// decodeDocument decodes the sections of the framed data, see ReadDocumentCtx.
+func decodeDocument(ctx context.Context, data []byte, blank rune) (*Document, error) {
+	d := &decoder{data: data}
+
+	comment := d.section("program comment")
+	columns := d.int("number of columns")
+	tableText := d.section("state table")
+	notes := d.section("state table comment")
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	t, err := parseTable(ctx, decodeCP1251(tableText), columns, blank)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Comment:  decodeText(comment),
+		Program:  t.program,
+		Start:    StartState,
+		Terminal: TerminalState,
+		Alphabet: t.alphabet,
+		Blank:    blank,
+		BlankSet: true,
+		Notes:    decodeText(notes),
+	}
+
+	doc.StateComments = stateComments(doc.Notes)
+
+	if len(d.data) > 0 {
+		offset := d.int("carriage offset")
+		start := d.int("saved tape position")
+
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		doc.Tape = &SavedTape{
+			Cells:    decodeCP1251(d.data),
+			Start:    start,
+			Carriage: start + offset,
+		}
+	}
+
+	return doc, nil
+}
+
+// maxFrameNumber bounds the lengths and the number of columns framing .tur files.
+// Text does not start with a smaller little-endian int32, as its fourth byte is NUL.
+const maxFrameNumber = 1 << 24
+
+// framed reports whether data starts like a .tur file, which may be truncated: the program
+// comment length, the number of columns and the state table length, as far as data goes,
+// are between 0 and maxFrameNumber.
+func framed(data []byte) bool {
+	d := &decoder{data: data}
+
+	for i, name := range []string{"program comment length", "number of columns", "state table length"} {
+		n := d.int(name)
+		if d.err != nil {
+			// data too short to hold even the program comment length is text
+			return i > 0
+		}
+
+		if n < 0 || n >= maxFrameNumber {
+			return false
+		}
+
+		if i == 0 {
+			if n > len(d.data) {
+				return true
+			}
+
+			d.skip(n)
+		}
+	}
+
+	return true
+}
+
+// statePattern matches the state names in the header of the state table.
+var statePattern = regexp.MustCompile(`Q\d+`)
+
+// readText reads the state table of data which is not framed as a .tur file, see ReadCtx.
+func readText(ctx context.Context, data []byte, blank rune) (turing.Program, []rune, error) {
+	var t *table
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err() //nolint:wrapcheck
+		}
+
+		fields := strings.Split(strings.TrimSuffix(line, "\r"), "\t")
+
+		if t == nil {
+			if len(fields) > 1 && statePattern.MatchString(strings.Join(fields[1:], " ")) {
+				t = newTable(fields[1:], blank)
+			}
+
+			continue
+		}
+
+		// the table ends with the first line which is not a row
+		if len(fields) == 1 {
+			break
+		}
+
+		if err := t.row(fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if t == nil {
+		return make(turing.Program), nil, nil
+	}
+
+	return t.program, t.alphabet, nil
+}
+
+// decoder reads the length-prefixed sections of a .tur file, the first error stops reading.
+type decoder struct {
+	data []byte
+
+	// offset of data in the file
+	offset int
+
+	err error
+}
+
+func (d *decoder) int(name string) int {
+	const size = 4
+
+	if d.err != nil {
+		return 0
+	}
+
+	if len(d.data) < size {
+		d.err = fmt.Errorf("%w: %s at offset %d, %d bytes left", ErrTruncatedFile, name, d.offset, len(d.data))
+		return 0
+	}
+
+	n := int32(binary.LittleEndian.Uint32(d.data)) //nolint:gosec
+	d.skip(size)
+
+	return int(n)
+}
+
+func (d *decoder) section(name string) []byte {
+	n := d.int(name + " length")
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 {
+		d.err = fmt.Errorf("%w: %s of %d bytes at offset %d", ErrCorruptFile, name, n, d.offset)
+		return nil
+	}
+
+	if n > len(d.data) {
+		d.err = fmt.Errorf("%w: %s of %d bytes at offset %d, %d bytes left", ErrTruncatedFile, name, n, d.offset, len(d.data))
+		return nil
+	}
+
+	section := d.data[:n]
+	d.skip(n)
+
+	return section
+}
+
+func (d *decoder) skip(n int) {
+	d.data = d.data[n:]
+	d.offset += n
+}
+
+// decodeText decodes a comment with line endings converted to "\n".
+func decodeText(b []byte) string {
+	return strings.ReplaceAll(decodeCP1251(b), "\r\n", "\n")
+}
+
+// parseTable parses the state table of a program with the given number of columns,
+// blank cells are read as the given symbol.
+func parseTable(ctx context.Context, text string, columns int, blank rune) (*table, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	if columns < 1 {
+		return nil, fmt.Errorf("%w: %d columns", ErrCorruptFile, columns)
+	}
+
+	// the first column holds the symbols of the rows
+	header := strings.Split(lines[0], "\t")
+	if header[0] != "" || len(header) != columns {
+		return nil, fmt.Errorf("%w: header %q for %d columns", ErrParseTable, lines[0], columns)
+	}
+
+	t := newTable(header[1:], blank)
+
+	for i, state := range t.states {
+		if state != "Q"+strconv.Itoa(i+1) {
+			return nil, fmt.Errorf("%w: state %q in column %d", ErrParseTable, state, i+1)
+		}
+	}
+
+	for _, line := range lines[1:] {
+		if ctx.Err() != nil {
+			return nil, ctx.Err() //nolint:wrapcheck
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if err := t.row(strings.Split(line, "\t")); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// stateCommentPattern matches the lines of the state table comment starting with a state name.
+var stateCommentPattern = regexp.MustCompile(`^(Q\d+)(?:\s*[-–—:]\s*|\s+)(.+)$`)
+
+// stateComments returns the comments of the states found in the state table comment.
+func stateComments(notes string) map[string]string {
+	comments := make(map[string]string)
+
+	for _, line := range strings.Split(notes, "\n") {
+		if m := stateCommentPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			comments[m[1]] = m[2]
+		}
+	}
+
+	return comments
+}
@@ -0,0 +1,249 @@
+package filereader_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/asphodex/go-turing/filereader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest
+func TestReadDocumentFileCtx_Testdata(t *testing.T) {
+	// the files were mangled after the simulator saved them, so their sections are lost,
+	// ReadFileCtx reads their state tables as text, see ReadCtx
+	tt := []struct {
+		name     string
+		states   int
+		alphabet string
+	}{
+		// Windows-1251 text decoded as UTF-8 and saved back, the lengths do not match the sections
+		{name: "valid_turing_with_input.tur", states: 71, alphabet: "1# "},
+		// NUL bytes and CR dropped too
+		{name: "valid_turing.tur", states: 34, alphabet: "1xya "},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testFilePath := filepath.Join("testdata", tc.name)
+
+			doc, err := filereader.ReadDocumentFileCtx(context.Background(), testFilePath)
+			require.ErrorIs(t, err, filereader.ErrCorruptFile)
+			assert.Nil(t, doc)
+
+			program, alphabet, err := filereader.ReadFileCtx(context.Background(), testFilePath)
+			require.NoError(t, err)
+			assert.Len(t, program, tc.states)
+			assert.Equal(t, []rune(tc.alphabet), alphabet)
+		})
+	}
+}
+
+func TestReadDocumentCtx_CorruptHeader(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{
+		Comment:  "add one",
+		Program:  program,
+		Alphabet: []rune("1"),
+		Blank:    turing.DefaultBlank,
+		Tape:     &filereader.SavedTape{Cells: "11", Start: 0, Carriage: 0},
+	}))
+
+	tt := []struct {
+		name    string
+		corrupt func(data []byte)
+	}{
+		{name: "negative number of columns", corrupt: func(data []byte) {
+			copy(data[4+len("add one"):], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+		}},
+		{name: "program comment length too large", corrupt: func(data []byte) {
+			data[3] = 0x40
+		}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := bytes.Clone(buf.Bytes())
+			tc.corrupt(data)
+
+			doc, err := filereader.ReadDocumentCtx(context.Background(), bytes.NewReader(data))
+			require.ErrorIs(t, err, filereader.ErrCorruptFile)
+			assert.Nil(t, doc)
+
+			// ReadCtx reads the state table as text
+			read, _, err := filereader.ReadCtx(context.Background(), bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, program, read)
+		})
+	}
+}
+
+func TestReadDocumentCtx_SavedTape(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{6, 0, 0, 0, 0xC7, 0xE0, 0xE4, 0xE0, 0xF7, 0xE0}
+	data = append(data, 2, 0, 0, 0)
+	data = append(data, 19, 0, 0, 0)
+	data = append(data, "\tQ1\r\n1\t1>1\r\n \t_<0\r\n"...)
+	data = append(data, 10, 0, 0, 0, 'Q', '1', ' ', '-', ' ', 0xEA, 0xEE, 0xED, 0xE5, 0xF6)
+	data = append(data, 2, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, '1', ' ', '1')
+
+	doc, err := filereader.ReadDocumentCtx(context.Background(), bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, filereader.Document{
+		Comment: "Задача",
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				' ': {NextState: "Q0", Move: turing.Left, Write: ' '},
+			},
+		},
+		Start:         filereader.StartState,
+		Terminal:      filereader.TerminalState,
+		Alphabet:      []rune("1 "),
+		Blank:         turing.DefaultBlank,
+		BlankSet:      true,
+		Notes:         "Q1 - конец",
+		StateComments: map[string]string{"Q1": "конец"},
+		Tape:          &filereader.SavedTape{Cells: "1 1", Start: -1, Carriage: 1},
+	}, *doc)
+
+	s, left := doc.Tape.Tape(doc.Blank).Trimmed()
+	assert.Equal(t, "1 1", s)
+	assert.Equal(t, -1, left)
+}
+
+func TestReadDocumentCtx_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	doc := filereader.Document{
+		Comment: "Прибавить единицу\nк числу",
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				' ': {NextState: "Q2", Move: turing.Stay, Write: '1'},
+			},
+			"Q2": {
+				'1': {NextState: "Q2", Move: turing.Left, Write: '1'},
+				' ': {NextState: "Q0", Move: turing.Right, Write: ' '},
+			},
+		},
+		Start:         "Q1",
+		Terminal:      "Q0",
+		Alphabet:      []rune("1 "),
+		Blank:         turing.DefaultBlank,
+		BlankSet:      true,
+		Notes:         "Q1: к концу числа\nQ2 — назад",
+		StateComments: map[string]string{"Q1": "к концу числа", "Q2": "назад"},
+		Tape:          &filereader.SavedTape{Cells: "111", Start: 2, Carriage: 3},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, doc))
+
+	read, err := filereader.ReadDocumentCtx(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, doc, *read)
+}
+
+func TestReadDocumentCtx_WithBlank(t *testing.T) {
+	t.Parallel()
+
+	doc := filereader.Document{
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				'#': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			},
+		},
+		Start:         filereader.StartState,
+		Terminal:      filereader.TerminalState,
+		Alphabet:      []rune("1#"),
+		Blank:         '#',
+		BlankSet:      true,
+		StateComments: map[string]string{},
+		Tape:          &filereader.SavedTape{Cells: "1 1", Start: 0, Carriage: 0},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, doc))
+
+	// the blank row is a space in the file
+	assert.Contains(t, buf.String(), "\r\n1\t1>1\r\n \t1.0\r\n")
+
+	read, err := filereader.ReadDocumentCtx(context.Background(), bytes.NewReader(buf.Bytes()), filereader.WithBlank('#'))
+	require.NoError(t, err)
+	assert.Equal(t, doc, *read)
+
+	_, input := read.Input()
+	assert.Equal(t, "1#1", input.String())
+
+	// a row of the blank symbol cannot be read
+	_, err = filereader.ReadDocumentCtx(context.Background(), bytes.NewReader(buf.Bytes()), filereader.WithBlank('1'))
+	require.ErrorIs(t, err, filereader.ErrParseTable)
+
+	// zero is a blank symbol like any other
+	read, err = filereader.ReadDocumentCtx(context.Background(), bytes.NewReader(buf.Bytes()), filereader.WithBlank(0))
+	require.NoError(t, err)
+	assert.Equal(t, []rune("1\x00"), read.Alphabet)
+
+	machine, err := read.Machine()
+	require.NoError(t, err)
+
+	result, err := machine.Run(read.Input())
+	require.NoError(t, err)
+	assert.Equal(t, "Q0", result.State)
+}
+
+func TestReadDocumentCtx_Truncated(t *testing.T) {
+	t.Parallel()
+
+	_, err := filereader.ReadDocumentCtx(context.Background(), bytes.NewReader([]byte{10, 0, 0, 0, 'a'}))
+	require.ErrorIs(t, err, filereader.ErrTruncatedFile)
+}
+
+func TestReadCtx_CyrillicAlphabet(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			'а': {NextState: "Q1", Move: turing.Right, Write: 'б'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: ' '},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{
+		Program:  program,
+		Alphabet: []rune("аб"),
+		Blank:    turing.DefaultBlank,
+	}))
+
+	// the table is saved in Windows-1251
+	assert.Contains(t, buf.String(), "\r\n\xe0\t\xe1>1\r\n\xe1\t\r\n \t_.0\r\n")
+
+	read, alphabet, err := filereader.ReadCtx(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, program, read)
+	assert.Equal(t, []rune("аб "), alphabet)
+}
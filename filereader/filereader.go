@@ -1,26 +1,35 @@
-// Package filereader reads Turing machine programs from .tur files structured as follows:
+// Package filereader reads and writes Turing machine programs in .tur files
+// of the simulator https://kpolyakov.spb.ru/prog/turing.htm structured as follows:
 // 1. Program comment section;
 // 2. Program definition section;
 // 3. State table comment section;
 // 4. Saved tape section (optional).
 //
+// Sections are prefixed with their length in bytes as a little-endian int32, text is encoded
+// in Windows-1251 with CRLF line endings. The program definition is also preceded by the number
+// of the table columns, the saved tape is preceded by the carriage offset in the saved cells
+// and the position of the first cell, and takes the rest of the file.
+//
 // Program definition format:
 // <Set of states>
 // <Symbol from alphabet>\t<transition>\t<transition>...
 // <Symbol from alphabet>\t<transition>...
 // Where transitions are tab-delimited and each alphabet symbol begins a new line of
 // its corresponding transitions.
+//
+// Blank cells are written as '_' in transitions, the blank row of the state table is a space.
+// Programs read from files use turing.DefaultBlank for them unless another symbol
+// is set with WithBlank.
 package filereader
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
 	"unicode/utf8"
 
@@ -29,32 +38,67 @@ import (
 
 // ReadFileCtx reads file from given filepath and returns turing.Program in case of success,
 // else returns an error.
-func ReadFileCtx(ctx context.Context, filePath string) (program turing.Program, alphabet []rune, err error) {
+func ReadFileCtx(
+	ctx context.Context,
+	filePath string,
+	opts ...Option,
+) (program turing.Program, alphabet []rune, err error) {
+	file, err := openFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return ReadCtx(ctx, file, opts...)
+}
+
+// openFile opens the file at the given filepath for reading.
+func openFile(filePath string) (*os.File, error) {
 	path := filepath.Clean(filePath)
 
 	if _, err := os.Stat(path); err != nil {
-		return nil, nil, fmt.Errorf("file %q does not exist: %w", path, err)
+		return nil, fmt.Errorf("file %q does not exist: %w", path, err)
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, nil, fmt.Errorf("read file %q: %w", path, err)
+		return nil, fmt.Errorf("read file %q: %w", path, err)
 	}
 
-	defer func() {
-		_ = file.Close()
-	}()
-
-	return ReadCtx(ctx, file)
+	return file, nil
 }
 
 var (
 	// ErrParseTransition is returned when a transition field cannot be parsed correctly.
 	ErrParseTransition = errors.New("parse transition")
+
+	// ErrParseTable is returned when the state table does not match the number of its columns
+	// or a row does not start with a single symbol.
+	ErrParseTable = errors.New("parse table")
+
+	// ErrTruncatedFile is returned when the file ends in the middle of a section.
+	ErrTruncatedFile = errors.New("truncated file")
+
+	// ErrCorruptFile is returned when the sections of the file are not framed as in .tur files.
+	ErrCorruptFile = errors.New("corrupt file")
 )
 
-// ParseTransition parse field like 1>Q2 and returns the decomposed parts of the field.
+// blank is the notation of the blank symbol in transitions.
+const blank = '_'
+
+// ParseTransition parse field like 1>2 and returns the decomposed parts of the field,
+// the next state is returned as Q2. The field must hold a single symbol, a direction
+// and the number of the next state, anything else like 1>Q2 fails with ErrParseTransition.
+// The blank symbol '_' is returned as turing.DefaultBlank.
 func ParseTransition(field string) (turing.Transition, error) {
+	return parseTransition(field, turing.DefaultBlank)
+}
+
+// parseTransition is ParseTransition returning the given symbol for blank writes.
+func parseTransition(field string, blankSym rune) (turing.Transition, error) {
 	const transitionFieldsCount = 2
 
 	directionTable := map[rune]turing.Direction{
@@ -67,14 +111,18 @@ func ParseTransition(field string) (turing.Transition, error) {
 		if strings.ContainsRune(field, sep) {
 			fields := strings.Split(field, string(sep))
 
-			// 1>Q1
+			// 1>1
 			if len(fields) != transitionFieldsCount || fields[0] == "" || fields[1] == "" {
 				return turing.Transition{}, fmt.Errorf("%w: %s", ErrParseTransition, field)
 			}
 
-			write, _ := utf8.DecodeRuneInString(fields[0])
-			if write == '_' {
-				write = ' '
+			write, size := utf8.DecodeRuneInString(fields[0])
+			if size != len(fields[0]) || !isNumber(fields[1]) {
+				return turing.Transition{}, fmt.Errorf("%w: %s", ErrParseTransition, field)
+			}
+
+			if write == blank {
+				write = blankSym
 			}
 
 			return turing.Transition{
@@ -88,74 +136,105 @@ func ParseTransition(field string) (turing.Transition, error) {
 	return turing.Transition{}, fmt.Errorf("%w: no direction found", ErrParseTransition)
 }
 
-// ReadCtx read .tur files from the given io.Reader.
-func ReadCtx(ctx context.Context, r io.Reader) (turing.Program, []rune, error) {
-	scanner := bufio.NewScanner(r)
+// isNumber reports whether s consists of decimal digits only.
+func isNumber(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
 
-	var (
-		program  = make(turing.Program)
-		states   []string
-		alphabet []rune
-		inScope  bool
-	)
+	return s != ""
+}
 
-	statePattern := regexp.MustCompile(`Q\d+`)
+// ReadCtx read .tur files from the given io.Reader.
+// It returns the program and the alphabet of ReadDocumentCtx.
+//
+// Data which does not start like a .tur file, which ReadDocumentCtx rejects with ErrCorruptFile,
+// is read the way the reader did before the sections were known: the lines following
+// the first line of state names are the rows of the state table.
+// The program is empty if no state table is found.
+func ReadCtx(ctx context.Context, r io.Reader, opts ...Option) (turing.Program, []rune, error) {
+	c := newConfig(opts)
+
+	data, err := readAll(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for scanner.Scan() {
-		if ctx.Err() != nil {
-			return nil, nil, ctx.Err() //nolint:wrapcheck
-		}
+	if !framed(data) {
+		return readText(ctx, data, c.blank)
+	}
 
-		line := scanner.Text()
+	doc, err := decodeDocument(ctx, data, c.blank)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		fields := strings.Split(line, "\t")
+	return doc.Program, doc.Alphabet, nil
+}
 
-		if len(fields) == 0 {
-			continue
-		}
+// table collects the program from the rows of the state table.
+type table struct {
+	program  turing.Program
+	states   []string
+	alphabet []rune
 
-		if !inScope {
-			if len(fields) > 1 && statePattern.MatchString(strings.Join(fields[1:], " ")) {
-				states = fields[1:]
-				inScope = true
-			}
+	// symbol of the blank row and '_' in transitions
+	blank rune
+}
 
-			continue
-		}
+func newTable(states []string, blank rune) *table {
+	return &table{
+		program: make(turing.Program),
+		states:  states,
+		blank:   blank,
+	}
+}
 
-		if fields[0] == "" {
-			continue
-		}
+// row parses the row of the symbol in the first field, the transitions of the states
+// follow in the order of the table columns.
+func (t *table) row(fields []string) error {
+	symbol, size := utf8.DecodeRuneInString(fields[0])
+	if size == 0 || size != len(fields[0]) {
+		return fmt.Errorf("%w: row of %q instead of a symbol", ErrParseTable, fields[0])
+	}
 
-		symbol, _ := utf8.DecodeRuneInString(fields[0])
-		alphabet = append(alphabet, symbol)
+	switch symbol {
+	case ' ':
+		symbol = t.blank
+	case t.blank:
+		return fmt.Errorf("%w: row of the blank symbol %q", ErrParseTable, symbol)
+	}
 
-		stateIndex := 0
+	if slices.Contains(t.alphabet, symbol) {
+		return fmt.Errorf("%w: several rows of %q", ErrParseTable, symbol)
+	}
 
-		for i := 1; i < len(fields); i++ {
-			if fields[i] == "" {
-				stateIndex++
-				continue
-			}
+	t.alphabet = append(t.alphabet, symbol)
 
-			transition, err := ParseTransition(fields[i])
-			if err != nil {
-				return nil, nil, err
-			}
+	for i, field := range fields[1:] {
+		if field == "" {
+			continue
+		}
 
-			if _, ok := program[states[stateIndex]]; !ok {
-				program[states[stateIndex]] = make(map[rune]turing.Transition)
-			}
+		if i >= len(t.states) {
+			return fmt.Errorf("%w: %s out of the state columns in row of %q", ErrParseTable, field, symbol)
+		}
 
-			program[states[stateIndex]][symbol] = transition
+		state := t.states[i]
 
-			stateIndex++
+		transition, err := parseTransition(field, t.blank)
+		if err != nil {
+			return fmt.Errorf("state %q, symbol %q: %w", state, symbol, err)
+		}
+
+		if _, ok := t.program[state]; !ok {
+			t.program[state] = make(map[rune]turing.Transition)
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, nil, fmt.Errorf("read program: %w", err)
+		t.program[state][symbol] = transition
 	}
 
-	return program, alphabet, nil
+	return nil
 }
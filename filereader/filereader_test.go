@@ -1,6 +1,7 @@
 package filereader_test
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -72,6 +73,71 @@ func TestReadCtx_InvalidData(t *testing.T) {
 	assert.Empty(t, alphabet)
 }
 
+func TestReadCtx_TruncatedFile(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{
+		Comment: "increment",
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			},
+		},
+		Alphabet: []rune("1"),
+		Blank:    turing.DefaultBlank,
+		Notes:    "Q1 - to the end",
+	}))
+
+	data := buf.Bytes()
+
+	// the file has no saved tape, so it cannot end before the state table comment does;
+	// less than 4 bytes cannot be told from text and are read as a file without state table
+	for n := 4; n < len(data); n++ {
+		program, _, err := filereader.ReadCtx(context.Background(), bytes.NewReader(data[:n]))
+		require.ErrorIs(t, err, filereader.ErrTruncatedFile, "%d bytes", n)
+		assert.Nil(t, program)
+	}
+}
+
+func TestReadCtx_InvalidTable(t *testing.T) {
+	t.Parallel()
+
+	file := func(columns int, table string) []byte {
+		data := []byte{0, 0, 0, 0, byte(columns), 0, 0, 0, byte(len(table)), 0, 0, 0}
+		data = append(data, table...)
+
+		return append(data, 0, 0, 0, 0)
+	}
+
+	tt := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "columns mismatch", data: file(3, "\tQ1\r\n"), err: filereader.ErrParseTable},
+		{name: "no columns", data: file(0, ""), err: filereader.ErrCorruptFile},
+		{name: "unexpected state", data: file(2, "\tQ2\r\n"), err: filereader.ErrParseTable},
+		{name: "field out of columns", data: file(2, "\tQ1\r\n1\t1>1\t1>1\r\n"), err: filereader.ErrParseTable},
+		{name: "row of several symbols", data: file(2, "\tQ1\r\n11\t1>1\r\n"), err: filereader.ErrParseTable},
+		{name: "repeated row", data: file(2, "\tQ1\r\n1\t1>1\r\n1\t1>0\r\n"), err: filereader.ErrParseTable},
+		{name: "invalid transition", data: file(2, "\tQ1\r\n1\t1>x\r\n"), err: filereader.ErrParseTransition},
+		{name: "truncated saved tape", data: append(file(2, "\tQ1\r\n"), 1, 0), err: filereader.ErrTruncatedFile},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			program, _, err := filereader.ReadCtx(context.Background(), bytes.NewReader(tc.data))
+			require.ErrorIs(t, err, tc.err)
+			assert.Nil(t, program)
+		})
+	}
+}
+
 func TestParseTransition(t *testing.T) {
 	t.Parallel()
 
@@ -130,6 +196,18 @@ func TestParseTransition(t *testing.T) {
 			transition: turing.Transition{},
 			err:        filereader.ErrParseTransition,
 		},
+		{
+			name:       "return error on next state out of numbers",
+			field:      "1>Q2",
+			transition: turing.Transition{},
+			err:        filereader.ErrParseTransition,
+		},
+		{
+			name:       "return error on several written symbols",
+			field:      "11>2",
+			transition: turing.Transition{},
+			err:        filereader.ErrParseTransition,
+		},
 		{
 			name:       "return error on invalid transition fields count",
 			field:      "Q2>",
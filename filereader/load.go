@@ -0,0 +1,85 @@
+package filereader
+
+import (
+	"context"
+
+	"github.com/asphodex/go-turing"
+)
+
+const (
+	// StartState is the state the simulator starts from, the first column of the state table.
+	StartState = "Q1"
+
+	// TerminalState is the state the simulator halts in.
+	TerminalState = "Q0"
+
+	// DefaultMaxTapeLength is the max tape length of machines created by Machine
+	// unless another one is given.
+	DefaultMaxTapeLength = 10000
+)
+
+// Options returns the options configuring a machine to run the program of the document
+// like the simulator does: its alphabet and blank symbol, its start and terminal states,
+// StartState and TerminalState if they are empty, and DefaultMaxTapeLength.
+func (doc *Document) Options() []turing.Option {
+	blankSym := doc.blankSymbol()
+
+	alphabet := make([]rune, 0, len(doc.Alphabet))
+	for _, sym := range doc.Alphabet {
+		if sym != blankSym {
+			alphabet = append(alphabet, sym)
+		}
+	}
+
+	start := doc.Start
+	if start == "" {
+		start = StartState
+	}
+
+	terminal := doc.Terminal
+	if terminal == "" {
+		terminal = TerminalState
+	}
+
+	return []turing.Option{
+		turing.WithAlphabet(string(alphabet)),
+		turing.WithBlank(blankSym),
+		turing.WithStartState(start),
+		turing.WithTerminalState(terminal),
+		turing.WithMaxTapeLength(DefaultMaxTapeLength),
+	}
+}
+
+// Machine creates a machine running the program of the document configured by Options,
+// the given options are applied after them.
+func (doc *Document) Machine(opts ...turing.Option) (*turing.Machine, error) {
+	return turing.New(doc.Program, append(doc.Options(), opts...)...)
+}
+
+// Input returns the carriage position and the tape the simulator starts with:
+// the saved tape, or an empty tape with the carriage at 0 if the file has none.
+func (doc *Document) Input() (int, *turing.Tape) {
+	if doc.Tape == nil {
+		return 0, turing.NewTape(doc.blankSymbol())
+	}
+
+	return doc.Tape.Carriage, doc.Tape.Tape(doc.blankSymbol())
+}
+
+// LoadFileCtx reads the .tur file from given filepath and creates a machine running its program,
+// see Document.Machine. The document is returned to get the saved tape with Document.Input.
+// The program is read with turing.DefaultBlank, use ReadDocumentFileCtx with WithBlank
+// and Document.Machine for another blank symbol.
+func LoadFileCtx(ctx context.Context, filePath string, opts ...turing.Option) (*turing.Machine, *Document, error) {
+	doc, err := ReadDocumentFileCtx(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	machine, err := doc.Machine(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return machine, doc, nil
+}
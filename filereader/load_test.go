@@ -0,0 +1,104 @@
+package filereader_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/asphodex/go-turing/filereader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFileCtx(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "inc.tur")
+
+	// increment the number and get back to its first symbol
+	require.NoError(t, filereader.WriteFile(path, filereader.Document{
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				' ': {NextState: "Q2", Move: turing.Left, Write: '1'},
+			},
+			"Q2": {
+				'1': {NextState: "Q2", Move: turing.Left, Write: '1'},
+				' ': {NextState: "Q0", Move: turing.Right, Write: ' '},
+			},
+		},
+		Alphabet: []rune("1"),
+		Blank:    turing.DefaultBlank,
+		Tape:     &filereader.SavedTape{Cells: " 11", Start: -1, Carriage: 0},
+	}))
+
+	machine, doc, err := filereader.LoadFileCtx(context.Background(), path, turing.WithMaxSteps(100))
+	require.NoError(t, err)
+	assert.Equal(t, filereader.StartState, doc.Start)
+	assert.Equal(t, filereader.TerminalState, doc.Terminal)
+
+	carriage, input := doc.Input()
+	assert.Equal(t, 0, carriage)
+
+	result, err := machine.Run(carriage, input)
+	require.NoError(t, err)
+	assert.Equal(t, "Q0", result.State)
+	assert.Equal(t, "111", result.Tape.String())
+	assert.Equal(t, 0, machine.Snapshot().Carriage)
+}
+
+//nolint:paralleltest
+func TestLoadFileCtx_Testdata(t *testing.T) {
+	for _, name := range []string{"valid_turing.tur", "valid_turing_with_input.tur"} {
+		t.Run(name, func(t *testing.T) {
+			// the files were mangled after the simulator saved them
+			machine, doc, err := filereader.LoadFileCtx(context.Background(), filepath.Join("testdata", name))
+			require.ErrorIs(t, err, filereader.ErrCorruptFile)
+			assert.Nil(t, machine)
+			assert.Nil(t, doc)
+		})
+	}
+}
+
+func TestDocument_Input_WithoutTape(t *testing.T) {
+	t.Parallel()
+
+	carriage, input := (&filereader.Document{Blank: turing.DefaultBlank}).Input()
+	assert.Equal(t, 0, carriage)
+	assert.Equal(t, 0, input.Len())
+}
+
+func TestDocument_Machine_DefaultStates(t *testing.T) {
+	t.Parallel()
+
+	// empty start and terminal states stand for StartState and TerminalState
+	doc := filereader.Document{
+		Program:  turing.Program{"Q1": {'1': {NextState: "Q0", Move: turing.Stay, Write: ' '}}},
+		Alphabet: []rune("1 "),
+		Blank:    turing.DefaultBlank,
+	}
+
+	machine, err := doc.Machine()
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("1", 0))
+	require.NoError(t, err)
+	assert.Equal(t, filereader.TerminalState, result.State)
+	assert.Empty(t, result.Tape.String())
+}
+
+func TestDocument_Machine_MissingStartState(t *testing.T) {
+	t.Parallel()
+
+	doc := filereader.Document{
+		Program:  turing.Program{"Q2": {'1': {NextState: "Q0", Move: turing.Stay, Write: '1'}}},
+		Start:    filereader.StartState,
+		Terminal: filereader.TerminalState,
+		Alphabet: []rune("1 "),
+		Blank:    turing.DefaultBlank,
+	}
+
+	_, err := doc.Machine()
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+}
@@ -0,0 +1,33 @@
+package filereader
+
+import "github.com/asphodex/go-turing"
+
+// Option configures how a .tur file is read.
+type Option func(*config)
+
+// config collects the reading configuration set by options.
+type config struct {
+	blank rune
+}
+
+// WithBlank sets the symbol the blank cells of the file are read as, the blank row
+// of the state table and '_' in transitions. turing.DefaultBlank is used by default.
+// Use the same symbol as turing.WithBlank of the machine running the program.
+func WithBlank(blank rune) Option {
+	return func(c *config) {
+		c.blank = blank
+	}
+}
+
+// newConfig applies the options over the defaults.
+func newConfig(opts []Option) config {
+	c := config{
+		blank: turing.DefaultBlank,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
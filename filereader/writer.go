@@ -0,0 +1,251 @@
+package filereader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/asphodex/go-turing"
+)
+
+// ErrInvalidState is returned when a state cannot be written as a column of the state table.
+var ErrInvalidState = errors.New("invalid state")
+
+// WriteFile writes the document to a .tur file at the given filepath,
+// replacing the file if it exists.
+func WriteFile(filePath string, doc Document) error {
+	path := filepath.Clean(filePath)
+
+	var buf bytes.Buffer
+
+	if err := Write(&buf, doc); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Write writes the document in the .tur format to the given io.Writer.
+// States of the program must be named Q1, Q2... with Q0 as the halting state, like ReadCtx
+// names them, and blank cells must hold the blank symbol of the document. The state table has a column for every
+// state up to the greatest one, the start state of the simulator is Q1.
+func Write(w io.Writer, doc Document) error {
+	if doc.Start != "" && doc.Start != StartState {
+		return fmt.Errorf("%w: start state %q, the simulator starts in %s", ErrInvalidState, doc.Start, StartState)
+	}
+
+	if doc.Terminal != "" && doc.Terminal != TerminalState {
+		return fmt.Errorf("%w: terminal state %q, the simulator halts in %s", ErrInvalidState, doc.Terminal, TerminalState)
+	}
+
+	comment, err := encodeText(doc.Comment)
+	if err != nil {
+		return fmt.Errorf("program comment: %w", err)
+	}
+
+	columns, table, err := encodeTable(doc.Program, doc.Alphabet, doc.blankSymbol())
+	if err != nil {
+		return err
+	}
+
+	notes, err := encodeText(doc.Notes)
+	if err != nil {
+		return fmt.Errorf("state table comment: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	writeSection(&buf, comment)
+	writeInt(&buf, columns+1)
+	writeSection(&buf, table)
+	writeSection(&buf, notes)
+
+	if doc.Tape != nil {
+		cells, err := encodeCP1251(doc.Tape.Cells)
+		if err != nil {
+			return fmt.Errorf("saved tape: %w", err)
+		}
+
+		writeInt(&buf, doc.Tape.Carriage-doc.Tape.Start)
+		writeInt(&buf, doc.Tape.Start)
+		buf.Write(cells)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write program: %w", err)
+	}
+
+	return nil
+}
+
+// writeSection writes the section prefixed with its length.
+func writeSection(buf *bytes.Buffer, section []byte) {
+	writeInt(buf, len(section))
+	buf.Write(section)
+}
+
+func writeInt(buf *bytes.Buffer, n int) {
+	_ = binary.Write(buf, binary.LittleEndian, int32(n)) //nolint:gosec
+}
+
+// encodeText encodes a comment with CRLF line endings.
+func encodeText(s string) ([]byte, error) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	return encodeCP1251(strings.ReplaceAll(s, "\n", "\r\n"))
+}
+
+// encodeTable returns the number of states and the state table of the program
+// with the given blank symbol.
+func encodeTable(program turing.Program, alphabet []rune, blankSym rune) (int, []byte, error) {
+	columns := 0
+
+	for state, transitions := range program {
+		n, err := stateNumber(state)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if n == 0 {
+			return 0, nil, fmt.Errorf("%w: transitions of the halting state %q", ErrInvalidState, state)
+		}
+
+		columns = max(columns, n)
+
+		for _, transition := range transitions {
+			n, err := stateNumber(transition.NextState)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			columns = max(columns, n)
+		}
+	}
+
+	rows := make([]rune, 0, len(alphabet)+1)
+	for _, sym := range alphabet {
+		if sym != blankSym && !slices.Contains(rows, sym) {
+			rows = append(rows, sym)
+		}
+	}
+
+	rows = append(rows, blankSym)
+
+	for state, transitions := range program {
+		for sym := range transitions {
+			if !slices.Contains(rows, sym) {
+				return 0, nil, fmt.Errorf("%w: %q read by state %q", turing.ErrUnexpectedSymbol, sym, state)
+			}
+		}
+	}
+
+	var sb strings.Builder
+
+	for i := 1; i <= columns; i++ {
+		sb.WriteString("\tQ" + strconv.Itoa(i))
+	}
+
+	sb.WriteString("\r\n")
+
+	for _, sym := range rows {
+		switch err := checkSymbol(sym); {
+		case sym == blankSym:
+			sb.WriteByte(' ')
+		case err != nil:
+			return 0, nil, err
+		default:
+			sb.WriteRune(sym)
+		}
+
+		for i := 1; i <= columns; i++ {
+			sb.WriteByte('\t')
+
+			transition, ok := program["Q"+strconv.Itoa(i)][sym]
+			if !ok {
+				continue
+			}
+
+			field, err := formatTransition(transition, blankSym)
+			if err != nil {
+				return 0, nil, fmt.Errorf("state %q, symbol %q: %w", "Q"+strconv.Itoa(i), sym, err)
+			}
+
+			sb.WriteString(field)
+		}
+
+		sb.WriteString("\r\n")
+	}
+
+	table, err := encodeCP1251(sb.String())
+	if err != nil {
+		return 0, nil, fmt.Errorf("state table: %w", err)
+	}
+
+	return columns, table, nil
+}
+
+// stateNumber returns the number of the state named like Q12.
+func stateNumber(state string) (int, error) {
+	digits, ok := strings.CutPrefix(state, "Q")
+	if !ok || digits == "" || (len(digits) > 1 && digits[0] == '0') {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidState, state)
+	}
+
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidState, state)
+	}
+
+	return n, nil
+}
+
+// checkSymbol checks that the non-blank symbol can be written in the state table.
+func checkSymbol(sym rune) error {
+	if sym == blank || sym == ' ' || sym == '\t' || sym == '\r' || sym == '\n' {
+		return fmt.Errorf("%w: %q", turing.ErrUnexpectedSymbol, sym)
+	}
+
+	return nil
+}
+
+// formatTransition formats the transition as a field like 1>2, the opposite of ParseTransition.
+// Writes of the given blank symbol are formatted as '_'.
+func formatTransition(transition turing.Transition, blankSym rune) (string, error) {
+	directions := map[turing.Direction]byte{
+		turing.Right: '>',
+		turing.Left:  '<',
+		turing.Stay:  '.',
+	}
+
+	dir, ok := directions[transition.Move]
+	if !ok {
+		return "", fmt.Errorf("%w: %d", turing.ErrInvalidMoveDirection, transition.Move)
+	}
+
+	write := transition.Write
+
+	switch err := checkSymbol(write); {
+	case write == blankSym:
+		write = blank
+	case err != nil:
+		return "", err
+	}
+
+	n, err := stateNumber(transition.NextState)
+	if err != nil {
+		return "", err
+	}
+
+	return string(write) + string(dir) + strconv.Itoa(n), nil
+}
@@ -0,0 +1,277 @@
+package filereader_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/asphodex/go-turing/filereader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	doc := filereader.Document{
+		Comment: "Увеличить\nна 1",
+		Program: turing.Program{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+				' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			},
+			"Q3": {
+				' ': {NextState: "Q1", Move: turing.Left, Write: ' '},
+			},
+		},
+		Alphabet: []rune("1"),
+		Blank:    turing.DefaultBlank,
+		Notes:    "Q1 - конец",
+		Tape:     &filereader.SavedTape{Cells: "11", Start: -1, Carriage: 0},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, doc))
+
+	expected := []byte{15, 0, 0, 0, 0xD3, 0xE2, 0xE5, 0xEB, 0xE8, 0xF7, 0xE8, 0xF2, 0xFC, '\r', '\n', 0xED, 0xE0, ' ', '1'}
+	expected = append(expected, 4, 0, 0, 0)
+	expected = append(expected, 32, 0, 0, 0)
+	expected = append(expected, "\tQ1\tQ2\tQ3\r\n1\t1>1\t\t\r\n \t1.0\t\t_<1\r\n"...)
+	expected = append(expected, 10, 0, 0, 0, 'Q', '1', ' ', '-', ' ', 0xEA, 0xEE, 0xED, 0xE5, 0xF6)
+	expected = append(expected, 1, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, '1', '1')
+
+	assert.Equal(t, expected, buf.Bytes())
+
+	program, alphabet, err := filereader.ReadCtx(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, doc.Program, program)
+	assert.Equal(t, []rune("1 "), alphabet[:2])
+}
+
+func TestWrite_DefaultBlank(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	// a zero Blank stands for turing.DefaultBlank
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{Program: program, Alphabet: []rune("1")}))
+	assert.Contains(t, buf.String(), "\r\n1\t1>1\r\n \t1.0\r\n")
+
+	// unless it is set as the blank symbol
+	buf.Reset()
+
+	err := filereader.Write(&buf, filereader.Document{Program: program, Alphabet: []rune("1"), BlankSet: true})
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+
+	program["Q1"][0] = program["Q1"][' ']
+	delete(program["Q1"], ' ')
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{Program: program, Alphabet: []rune("1"), BlankSet: true}))
+	assert.Contains(t, buf.String(), "\r\n1\t1>1\r\n \t1.0\r\n")
+}
+
+func TestWrite_Errors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name string
+		doc  filereader.Document
+		err  error
+	}{
+		{
+			name: "state out of the .tur naming",
+			doc: filereader.Document{Program: turing.Program{
+				"A": {'1': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			}},
+			err: filereader.ErrInvalidState,
+		},
+		{
+			name: "next state out of the .tur naming",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {'1': {NextState: "Q01", Move: turing.Stay, Write: '1'}},
+			}, Alphabet: []rune("1")},
+			err: filereader.ErrInvalidState,
+		},
+		{
+			name: "transitions of the halting state",
+			doc: filereader.Document{Program: turing.Program{
+				"Q0": {'1': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			}},
+			err: filereader.ErrInvalidState,
+		},
+		{
+			name: "symbol out of the alphabet",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {'2': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			}, Alphabet: []rune("1")},
+			err: turing.ErrUnexpectedSymbol,
+		},
+		{
+			name: "symbol reserved for blanks",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {'1': {NextState: "Q0", Move: turing.Stay, Write: '_'}},
+			}, Alphabet: []rune("1")},
+			err: turing.ErrUnexpectedSymbol,
+		},
+		{
+			name: "space other than the blank symbol",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			}, Alphabet: []rune("1 "), Blank: '#'},
+			err: turing.ErrUnexpectedSymbol,
+		},
+		{
+			name: "invalid move",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {'1': {NextState: "Q0", Move: turing.Up, Write: '1'}},
+			}, Alphabet: []rune("1")},
+			err: turing.ErrInvalidMoveDirection,
+		},
+		{
+			name: "start state other than Q1",
+			doc: filereader.Document{Program: turing.Program{
+				"Q3": {'1': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			}, Start: "Q3", Alphabet: []rune("1"), Blank: turing.DefaultBlank},
+			err: filereader.ErrInvalidState,
+		},
+		{
+			name: "terminal state other than Q0",
+			doc: filereader.Document{Program: turing.Program{
+				"Q1": {'1': {NextState: "Q2", Move: turing.Stay, Write: '1'}},
+			}, Terminal: "Q2", Alphabet: []rune("1"), Blank: turing.DefaultBlank},
+			err: filereader.ErrInvalidState,
+		},
+		{
+			name: "comment out of Windows-1251",
+			doc:  filereader.Document{Comment: "日本"},
+			err:  filereader.ErrUnsupportedCharacter,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			require.ErrorIs(t, filereader.Write(&buf, tc.doc), tc.err)
+			assert.Zero(t, buf.Len())
+		})
+	}
+}
+
+//nolint:paralleltest
+func TestWrite_SimulatorTable(t *testing.T) {
+	// the file was saved by the simulator and mangled outside of the state table later:
+	// the table and the number of columns preceding it are the bytes the simulator wrote,
+	// as are the three high bytes of the table length, its low byte became "\uFFFD"
+	data, err := os.ReadFile(filepath.Join("testdata", "valid_turing_with_input.tur"))
+	require.NoError(t, err)
+
+	start := bytes.Index(data, []byte("\tQ1\t"))
+	require.Positive(t, start)
+
+	// the blank row is the last one
+	end := start + bytes.Index(data[start:], []byte("\r\n \t")) + 2
+	end += bytes.Index(data[end:], []byte("\r\n")) + 2
+
+	program, alphabet, err := filereader.ReadCtx(context.Background(), bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, filereader.Write(&buf, filereader.Document{
+		Program:  program,
+		Alphabet: alphabet,
+		Blank:    turing.DefaultBlank,
+	}))
+
+	written := buf.Bytes()
+	table := data[start:end]
+
+	// comment length, number of columns, table length, table
+	assert.Equal(t, []byte{0, 0, 0, 0}, written[:4])
+	assert.Equal(t, data[start-10:start-6], written[4:8])
+	assert.Equal(t, "\uFFFD", string(data[start-6:start-3]))
+	assert.Equal(t, data[start-3:start], written[9:12])
+	assert.Equal(t, byte(len(table)), written[8])
+	assert.True(t, bytes.Equal(table, written[12:12+len(table)]), "state table differs:\n%q\n%q", table, written[12:12+len(table)])
+}
+
+//nolint:paralleltest
+func TestWriteFile_RoundTrip(t *testing.T) {
+	tape := testdataTape(t)
+
+	for _, name := range []string{"valid_turing.tur", "valid_turing_with_input.tur"} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			program, alphabet, err := filereader.ReadFileCtx(ctx, filepath.Join("testdata", name))
+			require.NoError(t, err)
+
+			// the sections mangled in the file
+			doc := &filereader.Document{
+				Comment:       "Задание\nf(x,y)=(2-3y+2x)/4",
+				Program:       program,
+				Start:         filereader.StartState,
+				Terminal:      filereader.TerminalState,
+				Alphabet:      alphabet,
+				Blank:         turing.DefaultBlank,
+				BlankSet:      true,
+				Notes:         "Q1 - начало работы\nQ2: конец",
+				StateComments: map[string]string{"Q1": "начало работы", "Q2": "конец"},
+				Tape:          tape,
+			}
+
+			path := filepath.Join(t.TempDir(), name)
+
+			require.NoError(t, filereader.WriteFile(path, *doc))
+
+			written, err := filereader.ReadDocumentFileCtx(ctx, path)
+			require.NoError(t, err)
+			assert.Equal(t, doc, written)
+
+			// writing it again gives the same bytes
+			var first, second bytes.Buffer
+
+			require.NoError(t, filereader.Write(&first, *doc))
+			require.NoError(t, filereader.Write(&second, *written))
+			assert.True(t, bytes.Equal(first.Bytes(), second.Bytes()))
+		})
+	}
+}
+
+// testdataTape recovers the saved tape of valid_turing_with_input.tur. Its cells and
+// the carriage offset preceding the position of the first cell are the bytes the simulator wrote,
+// the four bytes of the position became "\uFFFD" each, so the first cell is put at -offset.
+func testdataTape(t *testing.T) *filereader.SavedTape {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "valid_turing_with_input.tur"))
+	require.NoError(t, err)
+
+	cells := bytes.LastIndex(data, []byte("\uFFFD")) + len("\uFFFD")
+	position := cells - 4*len("\uFFFD")
+	require.Equal(t, strings.Repeat("\uFFFD", 4), string(data[position:cells]))
+
+	offset := int(binary.LittleEndian.Uint32(data[position-4 : position]))
+	require.Equal(t, 16, offset)
+
+	tape := &filereader.SavedTape{Cells: string(data[cells:]), Start: -offset, Carriage: 0}
+	assert.Equal(t, "#       1      111        #", tape.Cells)
+
+	return tape
+}
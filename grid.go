@@ -0,0 +1,186 @@
+package turing
+
+import "strings"
+
+// Point is a cell position on a grid. X grows to the right and Y grows downwards,
+// so grids render with the first row on top.
+type Point struct {
+	X, Y int
+}
+
+// Rect is a rectangular region of a grid, Min and Max are inclusive.
+type Rect struct {
+	Min, Max Point
+}
+
+// Area returns the number of cells in the region.
+func (r Rect) Area() int {
+	return (r.Max.X - r.Min.X + 1) * (r.Max.Y - r.Min.Y + 1)
+}
+
+// Contains reports whether p is inside the region.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// extend returns the smallest region containing both r and p.
+func (r Rect) extend(p Point) Rect {
+	r.Min.X = min(r.Min.X, p.X)
+	r.Min.Y = min(r.Min.Y, p.Y)
+	r.Max.X = max(r.Max.X, p.X)
+	r.Max.Y = max(r.Max.Y, p.Y)
+
+	return r
+}
+
+// Grid is an infinite two-dimensional tape storing the written cells only.
+// Cells which were never written hold the blank symbol.
+// The zero value is an empty grid with DefaultBlank ready to use.
+type Grid struct {
+	cells map[Point]rune
+
+	// bounding box of the written cells, valid if cells is not empty
+	bounds Rect
+
+	// blank symbol, used if blankSet, DefaultBlank otherwise,
+	// so that any rune including zero can be the blank symbol
+	blank    rune
+	blankSet bool
+}
+
+// NewGrid creates an empty grid with the given blank symbol.
+func NewGrid(blank rune) *Grid {
+	return &Grid{blank: blank, blankSet: true}
+}
+
+// GridFromStrings creates a grid with DefaultBlank holding the rows, the first symbol
+// of the first row at the origin point. Spaces in rows are blank cells.
+func GridFromStrings(origin Point, rows ...string) *Grid {
+	g := &Grid{}
+
+	for y, row := range rows {
+		x := 0
+
+		for _, sym := range row {
+			if sym != g.Blank() {
+				g.Write(Point{X: origin.X + x, Y: origin.Y + y}, sym)
+			}
+
+			x++
+		}
+	}
+
+	return g
+}
+
+// Blank returns the blank symbol of the grid.
+func (g *Grid) Blank() rune {
+	if !g.blankSet {
+		return DefaultBlank
+	}
+
+	return g.blank
+}
+
+// Read returns the symbol at the given point or the blank symbol if the cell is empty.
+func (g *Grid) Read(p Point) rune {
+	if sym, ok := g.cells[p]; ok {
+		return sym
+	}
+
+	return g.Blank()
+}
+
+// Write puts the symbol to the given point.
+func (g *Grid) Write(p Point, sym rune) {
+	if g.cells == nil {
+		g.cells = make(map[Point]rune)
+	}
+
+	if len(g.cells) == 0 {
+		g.bounds = Rect{Min: p, Max: p}
+	} else {
+		g.bounds = g.bounds.extend(p)
+	}
+
+	g.cells[p] = sym
+}
+
+// Bounds returns the bounding box of the written cells, false is returned for an empty grid.
+func (g *Grid) Bounds() (Rect, bool) {
+	return g.bounds, len(g.cells) > 0
+}
+
+// Area returns the number of cells in the bounding box of the written cells.
+func (g *Grid) Area() int {
+	if len(g.cells) == 0 {
+		return 0
+	}
+
+	return g.bounds.Area()
+}
+
+// Render renders the cells of the region, one line per row.
+func (g *Grid) Render(r Rect) string {
+	var sb strings.Builder
+
+	for y := r.Min.Y; y <= r.Max.Y; y++ {
+		if y > r.Min.Y {
+			sb.WriteByte('\n')
+		}
+
+		for x := r.Min.X; x <= r.Max.X; x++ {
+			sb.WriteRune(g.Read(Point{X: x, Y: y}))
+		}
+	}
+
+	return sb.String()
+}
+
+// String renders the bounding box of the written cells.
+func (g *Grid) String() string {
+	r, ok := g.Bounds()
+	if !ok {
+		return ""
+	}
+
+	return g.Render(r)
+}
+
+// Clone returns an independent copy of the grid.
+func (g *Grid) Clone() *Grid {
+	c := &Grid{
+		cells:    make(map[Point]rune, len(g.cells)),
+		bounds:   g.bounds,
+		blank:    g.blank,
+		blankSet: g.blankSet,
+	}
+
+	for p, sym := range g.cells {
+		c.cells[p] = sym
+	}
+
+	return c
+}
+
+// withBlank returns a copy of the grid using the given blank symbol,
+// cells holding the blank symbol of g become blank cells of the copy.
+func (g *Grid) withBlank(blank rune) *Grid {
+	if g.Blank() == blank {
+		c := g.Clone()
+		c.blank = blank
+		c.blankSet = true
+
+		return c
+	}
+
+	c := NewGrid(blank)
+
+	for p, sym := range g.cells {
+		if sym != g.Blank() {
+			c.Write(p, sym)
+		}
+	}
+
+	return c
+}
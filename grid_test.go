@@ -0,0 +1,47 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid_ReadWrite(t *testing.T) {
+	t.Parallel()
+
+	grid := &turing.Grid{}
+	assert.Equal(t, 0, grid.Area())
+	assert.Equal(t, ' ', grid.Read(turing.Point{X: 5, Y: -5}))
+	assert.Empty(t, grid.String())
+
+	grid.Write(turing.Point{X: 1, Y: 1}, 'a')
+	grid.Write(turing.Point{X: -1, Y: 2}, 'b')
+	assert.Equal(t, 'a', grid.Read(turing.Point{X: 1, Y: 1}))
+	assert.Equal(t, 6, grid.Area())
+
+	bounds, ok := grid.Bounds()
+	assert.True(t, ok)
+	assert.Equal(t, turing.Rect{Min: turing.Point{X: -1, Y: 1}, Max: turing.Point{X: 1, Y: 2}}, bounds)
+	assert.Equal(t, "  a\nb  ", grid.String())
+	assert.Equal(t, "   \n  a", grid.Render(turing.Rect{Min: turing.Point{X: -1}, Max: turing.Point{X: 1, Y: 1}}))
+}
+
+func TestGridFromStrings(t *testing.T) {
+	t.Parallel()
+
+	grid := turing.GridFromStrings(turing.Point{X: -1, Y: -1}, "#.#", " # ")
+	assert.Equal(t, '#', grid.Read(turing.Point{X: -1, Y: -1}))
+	assert.Equal(t, '.', grid.Read(turing.Point{X: 0, Y: -1}))
+	assert.Equal(t, '#', grid.Read(turing.Point{X: 0, Y: 0}))
+	assert.Equal(t, "#.#\n # ", grid.String())
+
+	clone := grid.Clone()
+	clone.Write(turing.Point{X: 5, Y: 5}, '#')
+	assert.Equal(t, ' ', grid.Read(turing.Point{X: 5, Y: 5}))
+	assert.Equal(t, 6, grid.Area())
+	assert.Equal(t, 49, clone.Area())
+
+	assert.Equal(t, '_', turing.NewGrid('_').Read(turing.Point{}))
+	assert.Equal(t, rune(0), turing.NewGrid(0).Read(turing.Point{}))
+}
@@ -0,0 +1,344 @@
+package turing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMaxArea is returned when the max area parameter of a grid machine is zero.
+var ErrInvalidMaxArea = errors.New("invalid max area")
+
+// GridProgram for two-dimensional Turing machine: the same as Program,
+// but transitions may also move the carriage Up and Down.
+type GridProgram map[string]map[rune]Transition
+
+// Validate checks move, write and next state fields for program, and that every transition
+// is keyed on a symbol of the alphabet. Any of the halting states is a valid next state.
+// All the problems found are joined into the returned error, ordered by state and symbol.
+func (gp GridProgram) Validate(alphabet map[rune]struct{}, haltingStates ...string) error {
+	c := newProgramCheck(alphabet, haltingStates, func(state string) bool {
+		_, ok := gp[state]
+
+		return ok
+	})
+
+	for _, state := range sortedKeys(gp) {
+		for _, symbol := range sortedKeys(gp[state]) {
+			transition := gp[state][symbol]
+			at := fmt.Sprintf("state %q, symbol %q", state, symbol)
+
+			_, valid := gridMoves[transition.Move]
+
+			c.read(symbol, state)
+			c.move(transition.Move, valid, at)
+			c.write(transition.Write, at)
+			c.next(transition.NextState, at)
+		}
+	}
+
+	return c.err()
+}
+
+// ValidateStart is Validate also checking that the start state is defined in program
+// or is one of the halting states.
+func (gp GridProgram) ValidateStart(alphabet map[rune]struct{}, start string, haltingStates ...string) error {
+	return validateStart(gp, start, haltingStates, gp.Validate(alphabet, haltingStates...))
+}
+
+// gridMoves maps the directions a grid machine accepts to the carriage offsets.
+var gridMoves = map[Direction]Point{
+	Stay:  {},
+	Left:  {X: -1},
+	Right: {X: 1},
+	Up:    {Y: -1},
+	Down:  {Y: 1},
+}
+
+// GridMachine is a Turing machine working on a two-dimensional grid.
+// Instead of the tape length, the area of the bounding box of the written cells is limited.
+type GridMachine struct {
+	// current carriage position
+	carriage Point
+
+	grid *Grid
+
+	// bounding box of the cells the carriage has visited
+	visited Rect
+
+	state string
+
+	startState string
+
+	haltingStates map[string]HaltKind
+
+	alphabet map[rune]struct{}
+
+	// symbol of the empty cells, always part of the alphabet
+	blank rune
+
+	program GridProgram
+
+	// number of executed steps
+	steps uint
+
+	maxArea uint
+
+	maxSteps uint
+
+	// behaviour on undefined transitions
+	undefined UndefinedTransition
+
+	// the machine halted on an undefined transition
+	stuck bool
+}
+
+// GridResult describes a finished execution of a grid machine.
+type GridResult struct {
+	// Grid is the final grid.
+	Grid *Grid
+
+	// Visited is the bounding box of the cells the carriage has visited,
+	// render it with Grid.Render.
+	Visited Rect
+
+	// State is the halting state reached. When the machine halts on an undefined
+	// transition, it is the state the transition was missing for.
+	State string
+
+	// Kind is the kind of the reached halting state.
+	Kind HaltKind
+
+	// Steps is the number of steps made.
+	Steps uint
+}
+
+// NewGridMachine creates a new two-dimensional Turing machine running the program configured
+// by the given options. The alphabet, blank, start state, halting states, max area, max steps
+// and undefined transition options are used, the max area is required. The tracer, loop detection
+// and tape mode options fail with ErrUnsupportedOption.
+func NewGridMachine(program GridProgram, opts ...Option) (*GridMachine, error) {
+	c := newConfig(opts)
+	a := c.symbols()
+
+	if err := c.checkUnsupported("grid machine"); err != nil {
+		return nil, err
+	}
+
+	halting, err := c.checkStates()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.maxArea == 0 {
+		return nil, ErrInvalidMaxArea
+	}
+
+	if err := program.ValidateStart(a, c.startState, halting...); err != nil {
+		return nil, err
+	}
+
+	m := &GridMachine{
+		grid:          NewGrid(c.blank),
+		state:         c.startState,
+		startState:    c.startState,
+		haltingStates: c.haltingStates,
+		alphabet:      a,
+		blank:         c.blank,
+		program:       program,
+		maxArea:       c.maxArea,
+		maxSteps:      c.maxSteps,
+		undefined:     c.undefined,
+	}
+
+	return m, nil
+}
+
+// Run is RunCtx with the background context.
+func (m *GridMachine) Run(carriage Point, input *Grid) (GridResult, error) {
+	return m.RunCtx(context.Background(), carriage, input)
+}
+
+// RunCtx executes the program on the input grid with the carriage at the given point
+// until the machine halts. The input is not modified, nil input is a blank grid.
+func (m *GridMachine) RunCtx(ctx context.Context, carriage Point, input *Grid) (GridResult, error) {
+	m.Reset(carriage, input)
+
+	var (
+		ok  = true
+		err error
+	)
+
+	for ok {
+		if ctx.Err() != nil {
+			return GridResult{}, ctx.Err() //nolint:wrapcheck
+		}
+
+		ok, err = m.step()
+		if err != nil {
+			return GridResult{}, err
+		}
+	}
+
+	kind := m.haltingStates[m.state]
+	if m.stuck && m.undefined == UndefinedReject {
+		kind = Reject
+	}
+
+	return GridResult{
+		Grid:    m.grid,
+		Visited: m.visited,
+		State:   m.state,
+		Kind:    kind,
+		Steps:   m.steps,
+	}, nil
+}
+
+// Reset prepares the machine to run on the input grid step by step with Step.
+// The input is copied and is not modified by the machine, nil input is a blank grid.
+func (m *GridMachine) Reset(carriage Point, input *Grid) {
+	if input == nil {
+		input = NewGrid(m.blank)
+	}
+
+	m.grid = input.withBlank(m.blank)
+	m.carriage = carriage
+	m.visited = Rect{Min: carriage, Max: carriage}
+	m.state = m.startState
+	m.steps = 0
+	m.stuck = false
+}
+
+// Step makes a single step, false is returned if the machine has halted.
+func (m *GridMachine) Step() (bool, error) {
+	ok, err := m.step()
+
+	return ok && !m.Halted(), err
+}
+
+// Halted reports whether the machine has reached a halting state
+// or halted on an undefined transition.
+func (m *GridMachine) Halted() bool {
+	_, ok := m.haltingStates[m.state]
+
+	return ok || m.stuck
+}
+
+// State returns the current state.
+func (m *GridMachine) State() string {
+	return m.state
+}
+
+// Carriage returns the current carriage position.
+func (m *GridMachine) Carriage() Point {
+	return m.carriage
+}
+
+// Grid returns a copy of the current grid.
+func (m *GridMachine) Grid() *Grid {
+	return m.grid.Clone()
+}
+
+// Visited returns the bounding box of the cells the carriage has visited.
+func (m *GridMachine) Visited() Rect {
+	return m.visited
+}
+
+func (m *GridMachine) step() (bool, error) {
+	if m.Halted() {
+		return false, nil
+	}
+
+	sym := m.grid.Read(m.carriage)
+	if _, ok := m.alphabet[sym]; !ok {
+		return false, m.execError(ErrUnexpectedSymbol)
+	}
+
+	transition, ok := m.program[m.state][sym]
+	if !ok {
+		if m.undefined == UndefinedError {
+			return false, m.execError(ErrTransitionNotFound)
+		}
+
+		m.stuck = true
+
+		return false, nil
+	}
+
+	// is current transition an infinite loop?
+	if transition.Move == Stay && transition.NextState == m.state && transition.Write == sym {
+		return false, m.execError(ErrInfiniteLoop)
+	}
+
+	m.grid.Write(m.carriage, transition.Write)
+
+	offset := gridMoves[transition.Move]
+	m.carriage.X += offset.X
+	m.carriage.Y += offset.Y
+	m.visited = m.visited.extend(m.carriage)
+
+	m.state = transition.NextState
+	m.steps++
+
+	if uint(m.grid.Area()) >= m.maxArea {
+		return false, m.execError(ErrTapeOver)
+	}
+
+	if m.maxSteps > 0 && m.steps >= m.maxSteps {
+		return false, m.execError(ErrStepsExceeded)
+	}
+
+	return true, nil
+}
+
+// gridExcerptRadius is the number of cells on each side of the carriage kept in GridExecError.
+const gridExcerptRadius = 3
+
+// GridExecError is the ExecError of grid machines, it describes the configuration
+// the machine stopped in.
+type GridExecError struct {
+	Err error
+
+	// State is the current state of the machine.
+	State string
+
+	// Symbol is the symbol under the carriage.
+	Symbol rune
+
+	// Carriage is the carriage position.
+	Carriage Point
+
+	// Steps is the number of steps made before the failure.
+	Steps uint
+
+	// Grid is the region GridRect around the carriage rendered by Grid.Render.
+	Grid     string
+	GridRect Rect
+}
+
+func (e *GridExecError) Error() string {
+	return fmt.Sprintf("%v: state %q, symbol %q, carriage (%d, %d), step %d",
+		e.Err, e.State, e.Symbol, e.Carriage.X, e.Carriage.Y, e.Steps)
+}
+
+func (e *GridExecError) Unwrap() error {
+	return e.Err
+}
+
+// execError wraps err into GridExecError describing the current configuration.
+func (m *GridMachine) execError(err error) *GridExecError {
+	r := Rect{
+		Min: Point{X: m.carriage.X - gridExcerptRadius, Y: m.carriage.Y - gridExcerptRadius},
+		Max: Point{X: m.carriage.X + gridExcerptRadius, Y: m.carriage.Y + gridExcerptRadius},
+	}
+
+	return &GridExecError{
+		Err:      err,
+		State:    m.state,
+		Symbol:   m.grid.Read(m.carriage),
+		Carriage: m.carriage,
+		Steps:    m.steps,
+		Grid:     m.grid.Render(r),
+		GridRect: r,
+	}
+}
@@ -0,0 +1,209 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// antProgram is Langton's ant: the state is the heading, on a white cell the ant
+// turns right, on a black one it turns left, then flips the cell and moves forward.
+func antProgram() turing.GridProgram {
+	type heading struct {
+		state string
+		move  turing.Direction
+	}
+
+	headings := []heading{
+		{"N", turing.Up}, {"E", turing.Right}, {"S", turing.Down}, {"W", turing.Left},
+	}
+
+	program := make(turing.GridProgram)
+
+	for i, h := range headings {
+		right := headings[(i+1)%len(headings)]
+		left := headings[(i+len(headings)-1)%len(headings)]
+
+		program[h.state] = map[rune]turing.Transition{
+			' ': {NextState: right.state, Move: right.move, Write: '#'},
+			'#': {NextState: left.state, Move: left.move, Write: ' '},
+		}
+	}
+
+	return program
+}
+
+func newAntMachine(t *testing.T, opts ...turing.Option) *turing.GridMachine {
+	t.Helper()
+
+	machine, err := turing.NewGridMachine(
+		antProgram(),
+		append([]turing.Option{
+			turing.WithAlphabet("#"),
+			turing.WithStartState("N"),
+			turing.WithTerminalState("H"),
+			turing.WithMaxArea(100),
+		}, opts...)...,
+	)
+	require.NoError(t, err)
+
+	return machine
+}
+
+func TestGridMachine_Step(t *testing.T) {
+	t.Parallel()
+
+	machine := newAntMachine(t)
+	machine.Reset(turing.Point{}, nil)
+
+	for i := 0; i < 4; i++ {
+		running, err := machine.Step()
+		require.NoError(t, err)
+		assert.True(t, running)
+	}
+
+	assert.Equal(t, turing.Point{}, machine.Carriage())
+	assert.Equal(t, "N", machine.State())
+	assert.Equal(t, "##\n##", machine.Grid().String())
+
+	running, err := machine.Step()
+	require.NoError(t, err)
+	assert.True(t, running)
+	assert.Equal(t, turing.Point{X: -1}, machine.Carriage())
+	assert.Equal(t, "W", machine.State())
+
+	visited := machine.Visited()
+	assert.Equal(t, turing.Rect{Min: turing.Point{X: -1}, Max: turing.Point{X: 1, Y: 1}}, visited)
+	assert.Equal(t, "  #\n ##", machine.Grid().Render(visited))
+}
+
+func TestGridMachine_Run(t *testing.T) {
+	t.Parallel()
+
+	// walks up over the marks and halts on the first blank
+	machine, err := turing.NewGridMachine(
+		turing.GridProgram{
+			"Q1": {
+				'1': {NextState: "Q1", Move: turing.Up, Write: '1'},
+				'_': {NextState: "Q0", Move: turing.Right, Write: '1'},
+			},
+		},
+		turing.WithAlphabet("1"),
+		turing.WithBlank('_'),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxArea(20),
+	)
+	require.NoError(t, err)
+
+	input := turing.GridFromStrings(turing.Point{Y: -2}, "1", "1", "1")
+
+	result, err := machine.Run(turing.Point{}, input)
+	require.NoError(t, err)
+	assert.Equal(t, "Q0", result.State)
+	assert.Equal(t, uint(4), result.Steps)
+	assert.Equal(t, "1\n1\n1\n1", result.Grid.String())
+	assert.Equal(t, "1_\n1_\n1_\n1_", result.Grid.Render(result.Visited))
+	assert.Equal(t, 3, input.Area())
+}
+
+func TestGridMachine_Run_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := newAntMachine(t).Run(turing.Point{}, nil)
+	require.ErrorIs(t, err, turing.ErrTapeOver)
+
+	var execErr *turing.GridExecError
+	require.True(t, errors.As(err, &execErr))
+	assert.Positive(t, execErr.Steps)
+
+	_, err = newAntMachine(t, turing.WithMaxArea(100000), turing.WithMaxSteps(50)).Run(turing.Point{}, nil)
+	require.ErrorIs(t, err, turing.ErrStepsExceeded)
+
+	_, err = newAntMachine(t).Run(turing.Point{X: 1}, turing.GridFromStrings(turing.Point{X: 1}, "x#"))
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+	require.True(t, errors.As(err, &execErr))
+	assert.Equal(t, "N", execErr.State)
+	assert.Equal(t, 'x', execErr.Symbol)
+	assert.Equal(t, turing.Point{X: 1}, execErr.Carriage)
+	assert.Equal(t, uint(0), execErr.Steps)
+	assert.Equal(t, turing.Rect{Min: turing.Point{X: -2, Y: -3}, Max: turing.Point{X: 4, Y: 3}}, execErr.GridRect)
+	assert.Equal(t, "       \n       \n       \n   x#  \n       \n       \n       ", execErr.Grid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = newAntMachine(t).RunCtx(ctx, turing.Point{}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewGridMachine_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := turing.NewGridMachine(antProgram(), turing.WithStartState("N"), turing.WithTerminalState("H"))
+	require.ErrorIs(t, err, turing.ErrInvalidMaxArea)
+
+	_, err = turing.NewGridMachine(
+		turing.GridProgram{"Q1": {' ': {NextState: "Q0", Move: 3, Write: ' '}}},
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxArea(10),
+	)
+	require.ErrorIs(t, err, turing.ErrInvalidMoveDirection)
+
+	_, err = turing.NewGridMachine(
+		antProgram(),
+		turing.WithAlphabet("#"),
+		turing.WithStartState("N"),
+		turing.WithTerminalState("H"),
+		turing.WithMaxArea(10),
+		turing.WithTracer(turing.NewRecorder(0)),
+	)
+	require.ErrorIs(t, err, turing.ErrUnsupportedOption)
+
+	_, err = turing.NewGridMachine(
+		antProgram(),
+		turing.WithAlphabet("#"),
+		turing.WithStartState("X"),
+		turing.WithTerminalState("H"),
+		turing.WithMaxArea(10),
+	)
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+
+	// one-dimensional machines do not move up and down
+	_, err = turing.NewMachine("", "Q1", "Q0", turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Up, Write: ' '}},
+	}, 10, 0)
+	require.ErrorIs(t, err, turing.ErrInvalidMoveDirection)
+}
+
+func TestGridProgram_Validate_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.GridProgram{
+		"Q2": {'1': {NextState: "Q9", Move: turing.Up, Write: '1'}},
+		"Q1": {
+			'x': {NextState: "Q0", Move: turing.Down, Write: '1'},
+			' ': {NextState: "Q0", Move: 7, Write: 'y'},
+		},
+	}
+
+	expected := strings.Join([]string{
+		`invalid move direction: 7 for state "Q1", symbol ' '`,
+		`unexpected symbol: 'y' for state "Q1", symbol ' '`,
+		`unexpected symbol: 'x' read by state "Q1"`,
+		`state not found: "Q9" for state "Q2", symbol '1'`,
+	}, "\n")
+
+	// the order does not depend on the map iteration order
+	for range 10 {
+		require.EqualError(t, program.Validate(alphabet, "Q0"), expected)
+	}
+}
@@ -0,0 +1,115 @@
+package turing
+
+import "context"
+
+// HaltKind classifies halting states.
+type HaltKind int
+
+// Available kinds of halting states.
+const (
+	// Neutral halting state just stops the machine, like the terminal state does.
+	Neutral HaltKind = iota
+
+	// Accept halting state stops the machine accepting the input.
+	Accept
+
+	// Reject halting state stops the machine rejecting the input.
+	Reject
+)
+
+func (k HaltKind) String() string {
+	switch k {
+	case Neutral:
+		return "neutral"
+	case Accept:
+		return "accept"
+	case Reject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// UndefinedTransition selects what the machine does when no transition
+// is defined for the current state and symbol.
+type UndefinedTransition int
+
+// Available behaviours on undefined transitions.
+const (
+	// UndefinedError fails the execution with ErrTransitionNotFound, it is the default.
+	UndefinedError UndefinedTransition = iota
+
+	// UndefinedHalt halts the machine in the current state as a neutral halting state.
+	UndefinedHalt
+
+	// UndefinedReject halts the machine in the current state rejecting the input.
+	UndefinedReject
+)
+
+// Result describes a finished execution.
+type Result struct {
+	// Tape is the final tape.
+	Tape *Tape
+
+	// State is the halting state reached. When the machine halts on an undefined
+	// transition, it is the state the transition was missing for.
+	State string
+
+	// Kind is the kind of the reached halting state.
+	Kind HaltKind
+
+	// Steps is the number of steps made.
+	Steps uint
+}
+
+// SetUndefinedTransition selects the behaviour on undefined transitions, see UndefinedTransition.
+func (m *Machine) SetUndefinedTransition(u UndefinedTransition) {
+	m.undefined = u
+}
+
+// haltKind returns the kind of the halt the machine is in.
+func (m *Machine) haltKind() HaltKind {
+	if !m.stuck {
+		return m.kinds[m.state]
+	}
+
+	if m.undefined == UndefinedReject {
+		return Reject
+	}
+
+	return Neutral
+}
+
+// Run is RunCtx with the background context.
+func (m *Machine) Run(carriage int, input *Tape) (Result, error) {
+	return m.RunCtx(context.Background(), carriage, input)
+}
+
+// RunCtx executes the program like ExecTapeCtx does and reports
+// which halting state was reached.
+func (m *Machine) RunCtx(ctx context.Context, carriage int, input *Tape) (Result, error) {
+	m.ResetTape(carriage, input)
+
+	var (
+		ok  = true
+		err error
+	)
+
+	for ok {
+		if ctx.Err() != nil {
+			return Result{}, ctx.Err() //nolint:wrapcheck
+		}
+
+		ok, err = m.step()
+		if err != nil {
+			return Result{}, m.traceError(err)
+		}
+	}
+
+	return Result{
+		Tape:  m.tape,
+		State: m.compiled.states[m.state],
+		Kind:  m.haltKind(),
+		Steps: m.steps,
+	}, nil
+}
@@ -0,0 +1,242 @@
+package turing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evenProgram decides whether the unary input has an even number of ones.
+func evenProgram() turing.Program {
+	return turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "QA", Move: turing.Stay, Write: ' '},
+		},
+		"Q2": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "QR", Move: turing.Stay, Write: ' '},
+		},
+	}
+}
+
+// newEvenMachine creates a machine running program over "1" from Q1 with the given options.
+func newEvenMachine(program turing.Program, opts ...turing.Option) (*turing.Machine, error) {
+	return turing.New(
+		program,
+		append([]turing.Option{
+			turing.WithAlphabet("1"),
+			turing.WithStartState("Q1"),
+			turing.WithMaxTapeLength(100),
+			turing.WithMaxSteps(100),
+		}, opts...)...,
+	)
+}
+
+func TestMachine_Run_HaltingStates(t *testing.T) {
+	t.Parallel()
+
+	machine, err := newEvenMachine(
+		evenProgram(),
+		turing.WithHaltingState("QA", turing.Accept),
+		turing.WithHaltingState("QR", turing.Reject),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		input := turing.TapeFromString(strings.Repeat("1", i), 0)
+
+		result, err := machine.Run(0, input)
+		require.NoError(t, err)
+		assert.Equal(t, uint(i+1), result.Steps)
+
+		if i%2 == 0 {
+			assert.Equal(t, "QA", result.State)
+			assert.Equal(t, turing.Accept, result.Kind)
+		} else {
+			assert.Equal(t, "QR", result.State)
+			assert.Equal(t, turing.Reject, result.Kind)
+		}
+
+		assert.True(t, input.Equal(result.Tape))
+		assert.True(t, machine.Halted())
+	}
+}
+
+func TestNew_HaltingStates_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := newEvenMachine(evenProgram())
+	require.ErrorIs(t, err, turing.ErrTerminalStateEmpty)
+
+	_, err = newEvenMachine(
+		evenProgram(),
+		turing.WithHaltingState("", turing.Accept),
+		turing.WithHaltingState("QR", turing.Reject),
+	)
+	require.ErrorIs(t, err, turing.ErrTerminalStateEmpty)
+
+	// QR is not a halting state nor a program state
+	_, err = newEvenMachine(evenProgram(), turing.WithHaltingState("QA", turing.Accept))
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+}
+
+func TestMachine_Run_TerminalState(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		10,
+		10,
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, &turing.Tape{})
+	require.NoError(t, err)
+	assert.Equal(t, "Q0", result.State)
+	assert.Equal(t, turing.Neutral, result.Kind)
+	assert.Equal(t, "1", result.Tape.String())
+}
+
+func TestProgram_Validate_HaltingStates(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	require.NoError(t, evenProgram().Validate(alphabet, "QA", "QR"))
+	require.ErrorIs(t, evenProgram().Validate(alphabet, "QA"), turing.ErrStateNotFound)
+}
+
+func TestHaltKind_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "neutral", turing.Neutral.String())
+	assert.Equal(t, "accept", turing.Accept.String())
+	assert.Equal(t, "reject", turing.Reject.String())
+	assert.Equal(t, "unknown", turing.HaltKind(42).String())
+}
+
+func TestMachine_SetUndefinedTransition(t *testing.T) {
+	t.Parallel()
+
+	// Accept even number of ones, there is no transition for odd ones.
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "QA", Move: turing.Stay, Write: ' '},
+		},
+		"Q2": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+		},
+	}
+
+	tt := []struct {
+		name      string
+		undefined turing.UndefinedTransition
+		input     string
+
+		state string
+		kind  turing.HaltKind
+		steps uint
+		err   error
+	}{
+		{
+			name:      "error on undefined transition by default",
+			undefined: turing.UndefinedError,
+			input:     "111",
+			err:       turing.ErrTransitionNotFound,
+		},
+		{
+			name:      "halt on undefined transition",
+			undefined: turing.UndefinedHalt,
+			input:     "111",
+			state:     "Q2",
+			kind:      turing.Neutral,
+			steps:     3,
+		},
+		{
+			name:      "reject on undefined transition",
+			undefined: turing.UndefinedReject,
+			input:     "111",
+			state:     "Q2",
+			kind:      turing.Reject,
+			steps:     3,
+		},
+		{
+			name:      "accept with defined transitions",
+			undefined: turing.UndefinedReject,
+			input:     "11",
+			state:     "QA",
+			kind:      turing.Accept,
+			steps:     3,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			machine, err := newEvenMachine(program, turing.WithHaltingState("QA", turing.Accept))
+			require.NoError(t, err)
+
+			machine.SetUndefinedTransition(tc.undefined)
+
+			result, err := machine.Run(0, turing.TapeFromString(tc.input, 0))
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err != nil {
+				assert.False(t, machine.Halted())
+				return
+			}
+
+			assert.True(t, machine.Halted())
+			assert.Equal(t, tc.state, result.State)
+			assert.Equal(t, tc.kind, result.Kind)
+			assert.Equal(t, tc.steps, result.Steps)
+		})
+	}
+}
+
+func TestMachine_Step_UndefinedHalt(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		100,
+		100,
+	)
+	require.NoError(t, err)
+
+	machine.SetUndefinedTransition(turing.UndefinedHalt)
+	machine.Reset(0, map[int]rune{0: '1'})
+
+	running, err := machine.Step()
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	running, err = machine.Step()
+	require.NoError(t, err)
+	assert.False(t, running)
+	assert.True(t, machine.Snapshot().Halted)
+
+	// reset starts over
+	machine.Reset(0, map[int]rune{0: '1'})
+	assert.False(t, machine.Halted())
+}
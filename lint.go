@@ -0,0 +1,379 @@
+package turing
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity of a lint finding.
+type Severity int
+
+// Available severities.
+const (
+	// SeverityError marks a problem failing or hanging every execution reaching it.
+	SeverityError Severity = iota
+
+	// SeverityWarning marks a problem depending on the input or a suspicious pattern.
+	SeverityWarning
+
+	// SeverityInfo marks states which are never used.
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// Rule identifies the check which produced a lint finding.
+type Rule int
+
+// Available lint rules.
+const (
+	// RuleInvalidMove reports a transition with an invalid move direction.
+	RuleInvalidMove Rule = iota
+
+	// RuleUnexpectedWrite reports a transition writing a symbol out of the alphabet.
+	RuleUnexpectedWrite
+
+	// RuleStateNotFound reports a next state, or the start state, missing in the program.
+	RuleStateNotFound
+
+	// RuleUndefinedTransition reports a symbol of the alphabet without a transition
+	// in a state reachable from the start state.
+	RuleUndefinedTransition
+
+	// RuleNoHalt reports a state reachable from the start state with no path to a halting state.
+	RuleNoHalt
+
+	// RuleStayLoop reports a transition on a cycle of Stay transitions,
+	// which never ends once the machine enters it.
+	RuleStayLoop
+
+	// RuleRunsOff reports a state which, reading blanks, keeps moving in one direction
+	// and gets back to itself, so it runs off along the tape once past the written cells.
+	RuleRunsOff
+
+	// RuleUnreachable reports a state unreachable from the start state.
+	RuleUnreachable
+
+	// RuleDeadTransition reports a transition for a symbol out of the alphabet,
+	// which can never be read and fails the validation.
+	RuleDeadTransition
+)
+
+func (r Rule) String() string {
+	switch r {
+	case RuleInvalidMove:
+		return "invalid-move"
+	case RuleUnexpectedWrite:
+		return "unexpected-write"
+	case RuleStateNotFound:
+		return "state-not-found"
+	case RuleUndefinedTransition:
+		return "undefined-transition"
+	case RuleNoHalt:
+		return "no-halt"
+	case RuleStayLoop:
+		return "stay-loop"
+	case RuleRunsOff:
+		return "runs-off"
+	case RuleUnreachable:
+		return "unreachable"
+	case RuleDeadTransition:
+		return "dead-transition"
+	default:
+		return "unknown"
+	}
+}
+
+// Finding is a problem found by Lint.
+type Finding struct {
+	Severity Severity
+	Rule     Rule
+
+	// State and Symbol locate the finding in the transition table.
+	// Findings about a whole state have no symbol and HasSymbol is false.
+	State     string
+	Symbol    rune
+	HasSymbol bool
+
+	// Message describes the problem.
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.HasSymbol {
+		return fmt.Sprintf("%v: state %q, symbol %q: %s (%v)", f.Severity, f.State, f.Symbol, f.Message, f.Rule)
+	}
+
+	return fmt.Sprintf("%v: state %q: %s (%v)", f.Severity, f.State, f.Message, f.Rule)
+}
+
+// Lint checks program like ValidateStart does, reporting every problem as a finding located
+// in the transition table, together with the problems ValidateStart does not look for:
+// undefined transitions and states never halting reachable from the start state,
+// endless Stay loops, states running off along the tape over blanks and unreachable states.
+// The alphabet must include the blank symbol. Findings are sorted by state, symbol and rule.
+func Lint(program Program, alphabet map[rune]struct{}, blank rune, start string, haltingStates ...string) []Finding {
+	l := &linter{
+		program: program,
+		halting: make(map[string]struct{}, len(haltingStates)),
+	}
+
+	for _, state := range haltingStates {
+		l.halting[state] = struct{}{}
+	}
+
+	symbols := make([]rune, 0, len(alphabet))
+	for sym := range alphabet {
+		symbols = append(symbols, sym)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	// transitions which can be followed only, the others are reported once
+	valid := make(Program, len(program))
+
+	for state, transitions := range program {
+		valid[state] = make(map[rune]Transition, len(transitions))
+
+		for sym, transition := range transitions {
+			if l.checkTransition(state, sym, transition, alphabet) {
+				valid[state][sym] = transition
+			}
+		}
+	}
+
+	if !l.known(start) {
+		l.report(SeverityError, RuleStateNotFound, start, 0, false, "start state is not defined")
+
+		l.sort()
+
+		return l.findings
+	}
+
+	reachable := reachableStates(valid, start, haltingStates...)
+	halts := haltingReachers(valid, l.halting)
+
+	for state := range program {
+		if _, ok := reachable[state]; !ok {
+			l.report(SeverityInfo, RuleUnreachable, state, 0, false, "state is unreachable from the start state")
+			continue
+		}
+
+		if _, ok := l.halting[state]; ok {
+			continue
+		}
+
+		if _, ok := halts[state]; !ok {
+			l.report(SeverityError, RuleNoHalt, state, 0, false, "no halting state can be reached from the state")
+		}
+
+		for _, sym := range symbols {
+			if _, ok := program[state][sym]; !ok {
+				l.report(SeverityWarning, RuleUndefinedTransition, state, sym, true, "transition is not defined")
+			}
+		}
+
+		for sym := range valid[state] {
+			if l.stayLoop(valid, state, sym) {
+				l.report(SeverityError, RuleStayLoop, state, sym, true, "transition starts an endless loop of Stay moves")
+			}
+		}
+
+		if move, ok := l.runsOff(valid, state, blank); ok {
+			l.report(SeverityWarning, RuleRunsOff, state, blank, true, fmt.Sprintf("state keeps moving %s over blanks", directionName(move)))
+		}
+	}
+
+	l.sort()
+
+	return l.findings
+}
+
+// linter collects lint findings.
+type linter struct {
+	program  Program
+	halting  map[string]struct{}
+	findings []Finding
+}
+
+func (l *linter) report(severity Severity, rule Rule, state string, sym rune, hasSymbol bool, message string) {
+	l.findings = append(l.findings, Finding{
+		Severity:  severity,
+		Rule:      rule,
+		State:     state,
+		Symbol:    sym,
+		HasSymbol: hasSymbol,
+		Message:   message,
+	})
+}
+
+// known reports whether the state is a state of the program or a halting state.
+func (l *linter) known(state string) bool {
+	if _, ok := l.halting[state]; ok {
+		return true
+	}
+
+	_, ok := l.program[state]
+
+	return ok
+}
+
+// checkTransition reports the problems of a single transition and whether it can be followed:
+// dead transitions and transitions to undefined states cannot.
+func (l *linter) checkTransition(state string, sym rune, transition Transition, alphabet map[rune]struct{}) bool {
+	_, live := alphabet[sym]
+	if !live {
+		l.report(SeverityError, RuleDeadTransition, state, sym, true, "symbol is out of the alphabet and can never be read")
+	}
+
+	if transition.Move != Left && transition.Move != Right && transition.Move != Stay {
+		l.report(SeverityError, RuleInvalidMove, state, sym, true, fmt.Sprintf("invalid move direction %d", transition.Move))
+	}
+
+	if _, ok := alphabet[transition.Write]; !ok {
+		l.report(SeverityError, RuleUnexpectedWrite, state, sym, true, fmt.Sprintf("written symbol %q is out of the alphabet", transition.Write))
+	}
+
+	if !l.known(transition.NextState) {
+		l.report(SeverityError, RuleStateNotFound, state, sym, true, fmt.Sprintf("next state %q is not defined", transition.NextState))
+
+		return false
+	}
+
+	return live
+}
+
+// stayLoop reports whether following the Stay transitions from the state reading sym
+// gets back to the same state and symbol.
+func (l *linter) stayLoop(program Program, state string, sym rune) bool {
+	current, read := state, sym
+	seen := make(map[TransitionKey]struct{})
+
+	for {
+		transition, ok := program[current][read]
+		if !ok || transition.Move != Stay {
+			return false
+		}
+
+		if _, ok := l.halting[transition.NextState]; ok {
+			return false
+		}
+
+		current, read = transition.NextState, transition.Write
+
+		if current == state && read == sym {
+			return true
+		}
+
+		// a loop not passing through the state is reported for its own transitions
+		key := TransitionKey{State: current, Symbol: read}
+		if _, ok := seen[key]; ok {
+			return false
+		}
+
+		seen[key] = struct{}{}
+	}
+}
+
+// runsOff reports whether the transitions on blanks, starting from the state, keep moving
+// the carriage in one direction and get back to the state, and returns the direction.
+func (l *linter) runsOff(program Program, state string, blank rune) (Direction, bool) {
+	current := state
+	seen := make(map[string]struct{})
+
+	var move Direction
+
+	for {
+		transition, ok := program[current][blank]
+		if !ok || transition.Move == Stay || (move != 0 && transition.Move != move) {
+			return 0, false
+		}
+
+		if _, ok := l.halting[transition.NextState]; ok {
+			return 0, false
+		}
+
+		move, current = transition.Move, transition.NextState
+
+		if current == state {
+			return move, true
+		}
+
+		if _, ok := seen[current]; ok {
+			return 0, false
+		}
+
+		seen[current] = struct{}{}
+	}
+}
+
+func (l *linter) sort() {
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.State != b.State {
+			return a.State < b.State
+		}
+
+		if a.HasSymbol != b.HasSymbol {
+			return !a.HasSymbol
+		}
+
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+
+		return a.Rule < b.Rule
+	})
+}
+
+// haltingReachers returns the states of program from which a halting state can be reached.
+func haltingReachers(program Program, halting map[string]struct{}) map[string]struct{} {
+	previous := make(map[string][]string)
+
+	for state, transitions := range program {
+		for _, transition := range transitions {
+			previous[transition.NextState] = append(previous[transition.NextState], state)
+		}
+	}
+
+	reachers := make(map[string]struct{}, len(program))
+	queue := make([]string, 0, len(halting))
+
+	for state := range halting {
+		reachers[state] = struct{}{}
+		queue = append(queue, state)
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, prev := range previous[state] {
+			if _, ok := reachers[prev]; !ok {
+				reachers[prev] = struct{}{}
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	return reachers
+}
+
+func directionName(d Direction) string {
+	if d == Left {
+		return "left"
+	}
+
+	return "right"
+}
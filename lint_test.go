@@ -0,0 +1,127 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: 'x'},
+			' ': {NextState: "Q3", Move: 5, Write: ' '},
+			'y': {NextState: "Q7", Move: turing.Stay, Write: '1'},
+		},
+		"Q2": {
+			'1': {NextState: "Q4", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q9", Move: turing.Right, Write: ' '},
+		},
+		"Q3": {
+			'1': {NextState: "Q5", Move: turing.Stay, Write: ' '},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: ' '},
+		},
+		"Q4": {
+			' ': {NextState: "Q4", Move: turing.Right, Write: '1'},
+		},
+		"Q5": {
+			' ': {NextState: "Q3", Move: turing.Stay, Write: '1'},
+			'1': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q6": {
+			'1': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		// reached by the dead transition only
+		"Q7": {
+			'1': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: ' '},
+		},
+	}
+
+	findings := turing.Lint(program, alphabet, ' ', "Q1", "Q0")
+
+	type location struct {
+		severity turing.Severity
+		rule     turing.Rule
+		state    string
+		symbol   rune
+	}
+
+	locations := make([]location, 0, len(findings))
+	for _, f := range findings {
+		locations = append(locations, location{f.Severity, f.Rule, f.State, f.Symbol})
+	}
+
+	assert.Equal(t, []location{
+		{turing.SeverityError, turing.RuleInvalidMove, "Q1", ' '},
+		{turing.SeverityError, turing.RuleUnexpectedWrite, "Q1", '1'},
+		{turing.SeverityError, turing.RuleDeadTransition, "Q1", 'y'},
+		{turing.SeverityError, turing.RuleNoHalt, "Q2", 0},
+		{turing.SeverityError, turing.RuleStateNotFound, "Q2", ' '},
+		{turing.SeverityError, turing.RuleStayLoop, "Q3", '1'},
+		{turing.SeverityError, turing.RuleNoHalt, "Q4", 0},
+		{turing.SeverityWarning, turing.RuleRunsOff, "Q4", ' '},
+		{turing.SeverityWarning, turing.RuleUndefinedTransition, "Q4", '1'},
+		{turing.SeverityError, turing.RuleStayLoop, "Q5", ' '},
+		{turing.SeverityInfo, turing.RuleUnreachable, "Q6", 0},
+		{turing.SeverityInfo, turing.RuleUnreachable, "Q7", 0},
+	}, locations)
+
+	assert.Equal(t, `error: state "Q2", symbol ' ': next state "Q9" is not defined (state-not-found)`, findings[4].String())
+	assert.Equal(t, `error: state "Q4": no halting state can be reached from the state (no-halt)`, findings[6].String())
+}
+
+func TestLint_Clean(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	assert.Empty(t, turing.Lint(program, alphabet, ' ', "Q1", "Q0"))
+}
+
+func TestLint_HaltingStateTransitions(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{' ': {}}
+
+	// Q5 is reached by the transitions of the halting state Q0 only, which are never applied
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: ' '}},
+		"Q0": {' ': {NextState: "Q5", Move: turing.Stay, Write: ' '}},
+		"Q5": {' ': {NextState: "Q5", Move: turing.Right, Write: ' '}},
+	}
+
+	findings := turing.Lint(program, alphabet, ' ', "Q1", "Q0")
+	require.Len(t, findings, 1)
+	assert.Equal(t, turing.SeverityInfo, findings[0].Severity)
+	assert.Equal(t, turing.RuleUnreachable, findings[0].Rule)
+	assert.Equal(t, "Q5", findings[0].State)
+}
+
+func TestLint_StartStateNotFound(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	findings := turing.Lint(turing.Program{}, alphabet, ' ', "Q1", "Q0")
+
+	assert.Equal(t, []turing.Finding{{
+		Severity: turing.SeverityError,
+		Rule:     turing.RuleStateNotFound,
+		State:    "Q1",
+		Message:  "start state is not defined",
+	}}, findings)
+}
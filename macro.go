@@ -0,0 +1,201 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrMacroNotFound is returned when a macro calls a macro missing in the library.
+	ErrMacroNotFound = errors.New("macro not found")
+
+	// ErrRecursiveMacro is returned when a macro calls itself directly or through other macros,
+	// or when calls return to each other without ever entering a state.
+	ErrRecursiveMacro = errors.New("recursive macro")
+)
+
+// Macro is a reusable sub-machine inlined into a flat Program by Expand.
+type Macro struct {
+	Program Program
+
+	// Start and Terminal are the start and the terminal states of the macro.
+	Start, Terminal string
+
+	// Calls maps the calling states to the calls. Entering a calling state runs
+	// the called macro, calling states have no transitions of their own.
+	Calls map[string]Call
+}
+
+// Call of a macro from another macro.
+type Call struct {
+	// Macro is the name of the called macro in the library.
+	Macro string
+
+	// Return is the state of the calling macro entered when the called macro
+	// reaches its terminal state.
+	Return string
+}
+
+// Expand inlines the macros called by root, and the macros they call, into a flat Program.
+// The states of root keep their names. The states of a macro called from state S
+// are prefixed with "S.macro.", so each call site gets its own copy.
+// The terminal state of root is the terminal state of the program. The start state
+// of the program is returned, it is the start state of root unless root starts with a call.
+//
+// The program is not validated, New validates it over the alphabet of the machine.
+func Expand(root Macro, library map[string]Macro) (Program, string, error) {
+	e := &expander{
+		library:   library,
+		program:   make(Program),
+		resolving: make(map[string]struct{}),
+	}
+
+	in := instance{macro: root, exit: root.Terminal}
+
+	start, err := e.resolve(in, root.Start)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := e.expand(in); err != nil {
+		return nil, "", err
+	}
+
+	return e.program, start, nil
+}
+
+type expander struct {
+	library map[string]Macro
+
+	// the flat program being built
+	program Program
+
+	// calling states being resolved, to detect calls never entering a state
+	resolving map[string]struct{}
+}
+
+// instance is a macro inlined at a call site.
+type instance struct {
+	macro Macro
+
+	// names of the macros from root to this one, to detect recursion
+	path []string
+
+	// prefix of the state names, empty for root
+	prefix string
+
+	// state the terminal state of the macro is renamed to
+	exit string
+}
+
+// local returns the name of the instance state in the flat program.
+func (in instance) local(state string) string {
+	if in.prefix == "" {
+		return state
+	}
+
+	return namespaced(in.prefix, state)
+}
+
+// resolve returns the name of the state in the flat program. Calling states
+// are resolved to the start state of the called macro instance.
+func (e *expander) resolve(in instance, state string) (string, error) {
+	if state == in.macro.Terminal {
+		return in.exit, nil
+	}
+
+	call, ok := in.macro.Calls[state]
+	if !ok {
+		return in.local(state), nil
+	}
+
+	key := in.local(state)
+	if _, ok := e.resolving[key]; ok {
+		return "", fmt.Errorf("%w: calls from state %q never enter a state", ErrRecursiveMacro, key)
+	}
+
+	e.resolving[key] = struct{}{}
+	defer delete(e.resolving, key)
+
+	callee, err := e.callee(in, state, call)
+	if err != nil {
+		return "", err
+	}
+
+	return e.resolve(callee, callee.macro.Start)
+}
+
+// callee returns the instance of the macro called from the state.
+func (e *expander) callee(in instance, state string, call Call) (instance, error) {
+	macro, ok := e.library[call.Macro]
+	if !ok {
+		return instance{}, fmt.Errorf("%w: %q called from state %q", ErrMacroNotFound, call.Macro, in.local(state))
+	}
+
+	for _, name := range in.path {
+		if name == call.Macro {
+			return instance{}, fmt.Errorf("%w: %q called from state %q", ErrRecursiveMacro, call.Macro, in.local(state))
+		}
+	}
+
+	exit, err := e.resolve(in, call.Return)
+	if err != nil {
+		return instance{}, err
+	}
+
+	return instance{
+		macro:  macro,
+		path:   append(append([]string(nil), in.path...), call.Macro),
+		prefix: namespaced(in.local(state), call.Macro),
+		exit:   exit,
+	}, nil
+}
+
+// expand adds the states of the instance and of the macros it calls to the program.
+func (e *expander) expand(in instance) error {
+	program := make(Program, len(in.macro.Program))
+	names := make(map[string]string)
+
+	for state, transitions := range in.macro.Program {
+		if _, ok := in.macro.Calls[state]; ok || state == in.macro.Terminal {
+			continue
+		}
+
+		program[state] = transitions
+		names[state] = in.local(state)
+
+		for _, transition := range transitions {
+			next, err := e.resolve(in, transition.NextState)
+			if err != nil {
+				return err
+			}
+
+			names[transition.NextState] = next
+		}
+	}
+
+	for state, transitions := range renameStates(program, func(state string) string { return names[state] }) {
+		e.program[state] = transitions
+	}
+
+	calls := make([]string, 0, len(in.macro.Calls))
+	for state := range in.macro.Calls {
+		calls = append(calls, state)
+	}
+
+	sort.Strings(calls)
+
+	for _, state := range calls {
+		callee, err := e.callee(in, state, in.macro.Calls[state])
+		if err != nil {
+			return err
+		}
+
+		if err := e.expand(callee); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// macroLibrary holds inc, incrementing a unary number and getting back to its first symbol,
+// and inc2 calling inc twice.
+func macroLibrary() map[string]turing.Macro {
+	return map[string]turing.Macro{
+		"inc": {
+			Start:    "Q1",
+			Terminal: "Q0",
+			Program: turing.Program{
+				"Q1": {
+					'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+					' ': {NextState: "Q2", Move: turing.Left, Write: '1'},
+				},
+				"Q2": {
+					'1': {NextState: "Q2", Move: turing.Left, Write: '1'},
+					' ': {NextState: "Q0", Move: turing.Right, Write: ' '},
+				},
+			},
+		},
+		"inc2": {
+			Start:    "A",
+			Terminal: "Q0",
+			Calls: map[string]turing.Call{
+				"A": {Macro: "inc", Return: "B"},
+				"B": {Macro: "inc", Return: "Q0"},
+			},
+		},
+	}
+}
+
+func runExpanded(t *testing.T, program turing.Program, start, input string) string {
+	t.Helper()
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithStartState(start),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(20),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString(input, 0))
+	require.NoError(t, err)
+
+	return result.Tape.String()
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	program, start, err := turing.Expand(turing.Macro{
+		Start:    "A",
+		Terminal: "Q0",
+		Calls: map[string]turing.Call{
+			"A": {Macro: "inc", Return: "B"},
+			"B": {Macro: "inc", Return: "Q0"},
+		},
+	}, macroLibrary())
+	require.NoError(t, err)
+	assert.Equal(t, "A.inc.Q1", start)
+	assert.Len(t, program, 4)
+	assert.Equal(t, "B.inc.Q1", program["A.inc.Q2"][' '].NextState)
+	assert.Equal(t, "Q0", program["B.inc.Q2"][' '].NextState)
+
+	assert.Equal(t, "111", runExpanded(t, program, start, "1"))
+}
+
+func TestExpand_Nested(t *testing.T) {
+	t.Parallel()
+
+	// writes a mark, then adds two more by the nested calls
+	program, start, err := turing.Expand(turing.Macro{
+		Start:    "Q1",
+		Terminal: "Q0",
+		Program: turing.Program{
+			"Q1": {' ': {NextState: "C", Move: turing.Stay, Write: '1'}},
+		},
+		Calls: map[string]turing.Call{
+			"C": {Macro: "inc2", Return: "Q0"},
+		},
+	}, macroLibrary())
+	require.NoError(t, err)
+	assert.Equal(t, "Q1", start)
+	assert.Equal(t, "C.inc2.A.inc.Q1", program["Q1"][' '].NextState)
+	assert.Contains(t, program, "C.inc2.B.inc.Q2")
+
+	assert.Equal(t, "111", runExpanded(t, program, start, ""))
+}
+
+func TestExpand_Errors(t *testing.T) {
+	t.Parallel()
+
+	library := macroLibrary()
+	library["self"] = turing.Macro{
+		Start:    "A",
+		Terminal: "Q0",
+		Calls:    map[string]turing.Call{"A": {Macro: "self", Return: "Q0"}},
+	}
+	library["nop"] = turing.Macro{Start: "Q0", Terminal: "Q0"}
+
+	tt := []struct {
+		name  string
+		calls map[string]turing.Call
+		err   error
+	}{
+		{
+			name:  "not found",
+			calls: map[string]turing.Call{"A": {Macro: "dec", Return: "Q0"}},
+			err:   turing.ErrMacroNotFound,
+		},
+		{
+			name:  "recursive",
+			calls: map[string]turing.Call{"A": {Macro: "self", Return: "Q0"}},
+			err:   turing.ErrRecursiveMacro,
+		},
+		{
+			name: "returns to each other",
+			calls: map[string]turing.Call{
+				"A": {Macro: "nop", Return: "B"},
+				"B": {Macro: "nop", Return: "A"},
+			},
+			err: turing.ErrRecursiveMacro,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := turing.Expand(turing.Macro{Start: "A", Terminal: "Q0", Calls: tc.calls}, library)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package turing
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TransitionKey is the location of a transition in a program.
+type TransitionKey struct {
+	State  string
+	Symbol rune
+}
+
+// MinimizeReport describes what Minimize removed from the program.
+type MinimizeReport struct {
+	// DeadTransitions are the transitions for symbols out of the alphabet,
+	// which can never be read.
+	DeadTransitions []TransitionKey
+
+	// Unreachable are the states unreachable from the start state.
+	Unreachable []string
+
+	// Merged maps the removed states to the equivalent states replacing them.
+	Merged map[string]string
+}
+
+// Minimize returns the smallest program equivalent to the given one: the transitions for symbols
+// out of the alphabet and the states unreachable from the start state are removed
+// (the transitions of the halting states are never applied, so they reach nothing),
+// then the equivalent states are merged by partition refinement over the transition table.
+// The start and the halting states are kept, merged states are replaced by the smallest
+// equivalent state name, or by the start state if it is equivalent to them.
+// The alphabet must include the blank symbol.
+func Minimize(program Program, alphabet map[rune]struct{}, start string, haltingStates ...string) (Program, MinimizeReport, error) {
+	report := MinimizeReport{Merged: make(map[string]string)}
+
+	live := make(Program, len(program))
+
+	for state, transitions := range program {
+		live[state] = make(map[rune]Transition, len(transitions))
+
+		for sym, transition := range transitions {
+			if _, ok := alphabet[sym]; !ok {
+				report.DeadTransitions = append(report.DeadTransitions, TransitionKey{State: state, Symbol: sym})
+				continue
+			}
+
+			live[state][sym] = transition
+		}
+	}
+
+	// the dead transitions are reported instead of failing the validation
+	if err := live.ValidateStart(alphabet, start, haltingStates...); err != nil {
+		return nil, MinimizeReport{Merged: make(map[string]string)}, err
+	}
+
+	sort.Slice(report.DeadTransitions, func(i, j int) bool {
+		a, b := report.DeadTransitions[i], report.DeadTransitions[j]
+		if a.State != b.State {
+			return a.State < b.State
+		}
+
+		return a.Symbol < b.Symbol
+	})
+
+	reachable := reachableStates(live, start, haltingStates...)
+
+	for state := range live {
+		if _, ok := reachable[state]; !ok {
+			report.Unreachable = append(report.Unreachable, state)
+			delete(live, state)
+		}
+	}
+
+	// the transitions of the halting states are never applied,
+	// those leading to the removed states go with them
+	for _, state := range haltingStates {
+		for sym, transition := range live[state] {
+			_, defined := program[transition.NextState]
+			if _, kept := live[transition.NextState]; defined && !kept {
+				delete(live[state], sym)
+			}
+		}
+	}
+
+	sort.Strings(report.Unreachable)
+
+	representatives := equivalentStates(live, alphabet, start, haltingStates...)
+
+	for state, representative := range representatives {
+		if state != representative {
+			report.Merged[state] = representative
+			delete(live, state)
+		}
+	}
+
+	minimized := renameStates(live, func(state string) string {
+		if representative, ok := representatives[state]; ok {
+			return representative
+		}
+
+		return state
+	})
+
+	return minimized, report, nil
+}
+
+// reachableStates returns the states of program reachable from start, start included.
+// The machine stops in the halting states, so their transitions are not followed.
+func reachableStates(program Program, start string, haltingStates ...string) map[string]struct{} {
+	halting := make(map[string]struct{}, len(haltingStates))
+	for _, state := range haltingStates {
+		halting[state] = struct{}{}
+	}
+
+	reachable := map[string]struct{}{start: {}}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		if _, ok := halting[state]; ok {
+			continue
+		}
+
+		for _, transition := range program[state] {
+			if _, ok := reachable[transition.NextState]; !ok {
+				reachable[transition.NextState] = struct{}{}
+				queue = append(queue, transition.NextState)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// equivalentStates splits the states of program into blocks of states behaving the same way
+// and maps every state to the representative of its block. States start in one block,
+// which is split by the transitions of each state until no block splits anymore.
+// The halting states, even those having transitions, and the states out of program
+// are never merged: each of them is a block of its own.
+func equivalentStates(program Program, alphabet map[rune]struct{}, start string, haltingStates ...string) map[string]string {
+	symbols := make([]rune, 0, len(alphabet))
+	for sym := range alphabet {
+		symbols = append(symbols, sym)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
+	halting := make(map[string]struct{}, len(haltingStates))
+	for _, state := range haltingStates {
+		halting[state] = struct{}{}
+	}
+
+	states := make([]string, 0, len(program))
+	for state := range program {
+		if _, ok := halting[state]; !ok {
+			states = append(states, state)
+		}
+	}
+
+	sort.Strings(states)
+
+	// block of each state, halting states and states out of program are identified by their names
+	block := make(map[string]string, len(states))
+	for _, state := range states {
+		block[state] = ""
+	}
+
+	blockOf := func(state string) string {
+		if b, ok := block[state]; ok {
+			return "b" + b
+		}
+
+		return "h" + state
+	}
+
+	for blocks := 1; ; {
+		next := make(map[string]string, len(states))
+		ids := make(map[string]string)
+
+		for _, state := range states {
+			var sb strings.Builder
+
+			sb.WriteString(block[state])
+
+			for _, sym := range symbols {
+				sb.WriteByte('|')
+
+				transition, ok := program[state][sym]
+				if !ok {
+					continue
+				}
+
+				sb.WriteString(strconv.QuoteRune(transition.Write))
+				sb.WriteString(strconv.Itoa(int(transition.Move)))
+				sb.WriteString(strconv.Quote(blockOf(transition.NextState)))
+			}
+
+			// number the blocks to keep signatures short
+			id, ok := ids[sb.String()]
+			if !ok {
+				id = strconv.Itoa(len(ids))
+				ids[sb.String()] = id
+			}
+
+			next[state] = id
+		}
+
+		block = next
+
+		if len(ids) == blocks {
+			break
+		}
+
+		blocks = len(ids)
+	}
+
+	representatives := make(map[string]string, len(states))
+	chosen := make(map[string]string)
+
+	// the start state represents its block, then the smallest names
+	for _, state := range append([]string{start}, states...) {
+		b, ok := block[state]
+		if !ok {
+			continue
+		}
+
+		if _, ok := chosen[b]; !ok {
+			chosen[b] = state
+		}
+
+		representatives[state] = chosen[b]
+	}
+
+	return representatives
+}
@@ -0,0 +1,206 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	// Q2 and Q3 both move right to the end of the number, Q4 is never reached,
+	// the 'x' transition of Q1 can never be applied
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q3", Move: turing.Right, Write: '1'},
+			'x': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q2": {
+			'1': {NextState: "Q3", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q3": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q4": {
+			'1': {NextState: "Q1", Move: turing.Left, Write: '1'},
+		},
+	}
+
+	minimized, report, err := turing.Minimize(program, alphabet, "Q1", "Q0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []turing.TransitionKey{{State: "Q1", Symbol: 'x'}}, report.DeadTransitions)
+	assert.Equal(t, []string{"Q4"}, report.Unreachable)
+	assert.Equal(t, map[string]string{"Q3": "Q2"}, report.Merged)
+
+	assert.Equal(t, turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q2", Move: turing.Right, Write: '1'},
+		},
+		"Q2": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}, minimized)
+
+	for _, p := range []turing.Program{program, minimized} {
+		machine, err := turing.New(
+			p,
+			turing.WithAlphabet("1x"),
+			turing.WithStartState("Q1"),
+			turing.WithTerminalState("Q0"),
+			turing.WithMaxTapeLength(20),
+		)
+		require.NoError(t, err)
+
+		result, err := machine.Run(0, turing.TapeFromString("111", 0))
+		require.NoError(t, err)
+		assert.Equal(t, "1111", result.Tape.String())
+	}
+}
+
+func TestMinimize_StartRepresentsBlock(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	// A and Q1 are equivalent, Q1 is kept as the start state although A is smaller
+	program := turing.Program{
+		"A": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+		"Q1": {
+			'1': {NextState: "A", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	minimized, report, err := turing.Minimize(program, alphabet, "Q1", "Q0")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"A": "Q1"}, report.Merged)
+	assert.Equal(t, turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}, minimized)
+}
+
+func TestMinimize_KeepsDistinctStates(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	// Q2 and Q3 differ only by the halting states they reach
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q3", Move: turing.Right, Write: ' '},
+		},
+		"Q2": {
+			'1': {NextState: "QA", Move: turing.Stay, Write: '1'},
+		},
+		"Q3": {
+			'1': {NextState: "QR", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	minimized, report, err := turing.Minimize(program, alphabet, "Q1", "QA", "QR")
+	require.NoError(t, err)
+
+	assert.Empty(t, report.Merged)
+	assert.Empty(t, report.Unreachable)
+	assert.Empty(t, report.DeadTransitions)
+	assert.Equal(t, program, minimized)
+}
+
+func TestMinimize_KeepsHaltingStates(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	// the halting state H has the same rows as Q1 and Q2, but it halts
+	rows := func() map[rune]turing.Transition {
+		return map[rune]turing.Transition{
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			' ': {NextState: "H", Move: turing.Stay, Write: '1'},
+		}
+	}
+
+	program := turing.Program{"Q1": rows(), "Q2": rows(), "H": rows()}
+
+	minimized, report, err := turing.Minimize(program, alphabet, "Q1", "H")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Q2": "Q1"}, report.Merged)
+
+	expected := map[rune]turing.Transition{
+		'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+		' ': {NextState: "H", Move: turing.Stay, Write: '1'},
+	}
+
+	assert.Equal(t, turing.Program{"Q1": expected, "H": expected}, minimized)
+
+	machine, err := turing.New(
+		minimized,
+		turing.WithAlphabet("1"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("H"),
+		turing.WithMaxTapeLength(20),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("11", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "H", result.State)
+	assert.Equal(t, "111", result.Tape.String())
+}
+
+func TestMinimize_HaltingStateTransitions(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{' ': {}}
+
+	// Q5 is reached by the transitions of the halting state Q0 only, which are never applied
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: ' '}},
+		"Q0": {' ': {NextState: "Q5", Move: turing.Stay, Write: ' '}},
+		"Q5": {' ': {NextState: "Q5", Move: turing.Right, Write: ' '}},
+	}
+
+	minimized, report, err := turing.Minimize(program, alphabet, "Q1", "Q0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Q5"}, report.Unreachable)
+	assert.Equal(t, turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: ' '}},
+		"Q0": {},
+	}, minimized)
+
+	require.NoError(t, minimized.ValidateStart(alphabet, "Q1", "Q0"))
+}
+
+func TestMinimize_Errors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	_, _, err := turing.Minimize(turing.Program{
+		"Q1": {'1': {NextState: "Q2", Move: turing.Right, Write: '1'}},
+	}, alphabet, "Q1", "Q0")
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+
+	_, _, err = turing.Minimize(turing.Program{
+		"Q1": {'1': {NextState: "Q0", Move: turing.Right, Write: '1'}},
+	}, alphabet, "Q9", "Q0")
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+}
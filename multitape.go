@@ -0,0 +1,374 @@
+package turing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrTapesMismatch is returned when the number of symbols, moves, carriages or tapes
+// does not match the number of tapes of a multi-tape machine.
+var ErrTapesMismatch = errors.New("tapes count mismatch")
+
+// MultiProgram for multi-tape Turing machine. Transitions are keyed by the symbols
+// read from all the tapes, the first symbol from the first tape:
+// program["Q1"]["1 "] applies in the state Q1 reading '1' on the first tape
+// and a blank on the second one.
+type MultiProgram map[string]map[string]MultiTransition
+
+// MultiTransition is a transition of a multi-tape machine.
+// Write and Move hold a symbol to write and a move for each tape.
+type MultiTransition struct {
+	NextState string
+	Write     []rune
+	Move      []Direction
+}
+
+// Validate checks keys, move, write and next state fields for program running on the
+// given number of tapes. Any of the halting states is a valid next state.
+// All the problems found are joined into the returned error, ordered by state and symbols.
+func (mp MultiProgram) Validate(tapes int, alphabet map[rune]struct{}, haltingStates ...string) error {
+	c := newProgramCheck(alphabet, haltingStates, func(state string) bool {
+		_, ok := mp[state]
+
+		return ok
+	})
+
+	for _, state := range sortedKeys(mp) {
+		for _, symbols := range sortedKeys(mp[state]) {
+			transition := mp[state][symbols]
+			at := fmt.Sprintf("state %q, symbols %q", state, symbols)
+
+			if n := utf8.RuneCountInString(symbols); n != tapes {
+				c.add(fmt.Errorf("%w: %d symbols for %s", ErrTapesMismatch, n, at))
+			}
+
+			if len(transition.Write) != tapes || len(transition.Move) != tapes {
+				c.add(fmt.Errorf("%w: %d writes and %d moves for %s",
+					ErrTapesMismatch, len(transition.Write), len(transition.Move), at))
+			}
+
+			for _, sym := range symbols {
+				c.read(sym, state)
+			}
+
+			for _, move := range transition.Move {
+				c.move(move, isMove(move), at)
+			}
+
+			for _, write := range transition.Write {
+				c.write(write, at)
+			}
+
+			c.next(transition.NextState, at)
+		}
+	}
+
+	return c.err()
+}
+
+// ValidateStart is Validate also checking that the start state is defined in program
+// or is one of the halting states.
+func (mp MultiProgram) ValidateStart(tapes int, alphabet map[rune]struct{}, start string, haltingStates ...string) error {
+	return validateStart(mp, start, haltingStates, mp.Validate(tapes, alphabet, haltingStates...))
+}
+
+// MultiExecError is the ExecError of multi-tape machines, it describes the configuration
+// on every tape, the first one for the first tape.
+type MultiExecError struct {
+	Err error
+
+	// State is the current state of the machine.
+	State string
+
+	// Symbols are the symbols under the carriages.
+	Symbols string
+
+	// Carriages are the carriage positions.
+	Carriages []int
+
+	// Steps is the number of steps made before the failure.
+	Steps uint
+
+	// Tapes are excerpts of the tapes around the carriages,
+	// TapeStarts are the positions of their first cells.
+	Tapes      []string
+	TapeStarts []int
+}
+
+func (e *MultiExecError) Error() string {
+	return fmt.Sprintf("%v: state %q, symbols %q, carriages %v, step %d", e.Err, e.State, e.Symbols, e.Carriages, e.Steps)
+}
+
+func (e *MultiExecError) Unwrap() error {
+	return e.Err
+}
+
+// MultiMachine is a Turing machine with several tapes, each with its own carriage.
+// A step reads the symbols under all the carriages, then writes and moves on each tape
+// independently. The max tape length limit applies to every tape.
+type MultiMachine struct {
+	// current carriage position on each tape
+	carriages []int
+
+	tapes []*Tape
+
+	state string
+
+	startState string
+
+	haltingStates map[string]HaltKind
+
+	alphabet map[rune]struct{}
+
+	// symbol of the empty cells, always part of the alphabet
+	blank rune
+
+	program MultiProgram
+
+	// number of executed steps
+	steps uint
+
+	maxTapeLength uint
+
+	maxSteps uint
+
+	// behaviour on undefined transitions
+	undefined UndefinedTransition
+
+	// the machine halted on an undefined transition
+	stuck bool
+
+	// buffer for the symbols read in a step
+	key []byte
+}
+
+// MultiResult is the outcome of a halted multi-tape machine run.
+type MultiResult struct {
+	Tapes []*Tape
+	State string
+	Kind  HaltKind
+	Steps uint
+}
+
+// NewMultiMachine creates a new Turing machine with the given number of tapes
+// running the program configured by the given options. The alphabet, blank, start state,
+// halting states, max tape length, max steps and undefined transition options are used,
+// the tracer, loop detection and tape mode options fail with ErrUnsupportedOption.
+func NewMultiMachine(tapes int, program MultiProgram, opts ...Option) (*MultiMachine, error) {
+	if tapes < 1 {
+		return nil, fmt.Errorf("%w: %d tapes", ErrTapesMismatch, tapes)
+	}
+
+	c := newConfig(opts)
+	a := c.symbols()
+
+	if err := c.checkUnsupported("multi-tape machine"); err != nil {
+		return nil, err
+	}
+
+	halting, err := c.check()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := program.ValidateStart(tapes, a, c.startState, halting...); err != nil {
+		return nil, err
+	}
+
+	m := &MultiMachine{
+		carriages:     make([]int, tapes),
+		tapes:         make([]*Tape, tapes),
+		startState:    c.startState,
+		haltingStates: c.haltingStates,
+		alphabet:      a,
+		blank:         c.blank,
+		program:       program,
+		maxTapeLength: c.maxTapeLength,
+		maxSteps:      c.maxSteps,
+		undefined:     c.undefined,
+	}
+
+	for i := range m.tapes {
+		m.tapes[i] = NewTape(m.blank)
+	}
+
+	m.state = m.startState
+
+	return m, nil
+}
+
+// Exec runs the machine on the input tapes with the carriages at the given positions
+// and returns the resulting tapes. The inputs are not modified, nil inputs are blank tapes.
+func (m *MultiMachine) Exec(carriages []int, inputs []*Tape) ([]*Tape, error) {
+	return m.ExecCtx(context.Background(), carriages, inputs)
+}
+
+// ExecCtx is Exec stopping with the context error when ctx is done.
+func (m *MultiMachine) ExecCtx(ctx context.Context, carriages []int, inputs []*Tape) ([]*Tape, error) {
+	result, err := m.RunCtx(ctx, carriages, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Tapes, nil
+}
+
+// Run is Exec returning the halting state and its kind along with the tapes.
+func (m *MultiMachine) Run(carriages []int, inputs []*Tape) (MultiResult, error) {
+	return m.RunCtx(context.Background(), carriages, inputs)
+}
+
+// RunCtx is Run stopping with the context error when ctx is done.
+func (m *MultiMachine) RunCtx(ctx context.Context, carriages []int, inputs []*Tape) (MultiResult, error) {
+	if err := m.reset(carriages, inputs); err != nil {
+		return MultiResult{}, err
+	}
+
+	var (
+		ok  = true
+		err error
+	)
+
+	for ok {
+		if ctx.Err() != nil {
+			return MultiResult{}, ctx.Err() //nolint:wrapcheck
+		}
+
+		ok, err = m.step()
+		if err != nil {
+			return MultiResult{}, err
+		}
+	}
+
+	kind := m.haltingStates[m.state]
+	if m.stuck && m.undefined == UndefinedReject {
+		kind = Reject
+	}
+
+	return MultiResult{
+		Tapes: m.tapes,
+		State: m.state,
+		Kind:  kind,
+		Steps: m.steps,
+	}, nil
+}
+
+func (m *MultiMachine) reset(carriages []int, inputs []*Tape) error {
+	if len(carriages) != len(m.tapes) || len(inputs) != len(m.tapes) {
+		return fmt.Errorf("%w: %d carriages and %d inputs for %d tapes",
+			ErrTapesMismatch, len(carriages), len(inputs), len(m.tapes))
+	}
+
+	for i, input := range inputs {
+		if input == nil {
+			m.tapes[i] = NewTape(m.blank)
+		} else {
+			m.tapes[i] = input.withBlank(m.blank)
+		}
+	}
+
+	copy(m.carriages, carriages)
+	m.state = m.startState
+	m.steps = 0
+	m.stuck = false
+
+	return nil
+}
+
+// Halted reports whether the machine is in a halting state or stuck on an undefined transition.
+func (m *MultiMachine) Halted() bool {
+	_, ok := m.haltingStates[m.state]
+
+	return ok || m.stuck
+}
+
+// step makes one step, false is returned if the machine has halted.
+func (m *MultiMachine) step() (bool, error) {
+	if m.Halted() {
+		return false, nil
+	}
+
+	m.key = m.key[:0]
+
+	for i, tape := range m.tapes {
+		sym := tape.Read(m.carriages[i])
+		if _, ok := m.alphabet[sym]; !ok {
+			return false, m.execError(ErrUnexpectedSymbol)
+		}
+
+		m.key = utf8.AppendRune(m.key, sym)
+	}
+
+	transition, ok := m.program[m.state][string(m.key)]
+	if !ok {
+		if m.undefined == UndefinedError {
+			return false, m.execError(ErrTransitionNotFound)
+		}
+
+		m.stuck = true
+
+		return false, nil
+	}
+
+	// is current transition an infinite loop?
+	if transition.NextState == m.state && m.unchanged(transition) {
+		return false, m.execError(ErrInfiniteLoop)
+	}
+
+	for i, tape := range m.tapes {
+		tape.Write(m.carriages[i], transition.Write[i])
+		m.carriages[i] += int(transition.Move[i])
+	}
+
+	m.state = transition.NextState
+	m.steps++
+
+	for _, tape := range m.tapes {
+		if uint(tape.Len()) >= m.maxTapeLength {
+			return false, m.execError(ErrTapeOver)
+		}
+	}
+
+	if m.maxSteps > 0 && m.steps >= m.maxSteps {
+		return false, m.execError(ErrStepsExceeded)
+	}
+
+	return true, nil
+}
+
+// unchanged reports whether the transition neither moves nor writes anything new on all tapes.
+func (m *MultiMachine) unchanged(transition MultiTransition) bool {
+	for i, tape := range m.tapes {
+		if transition.Move[i] != Stay || transition.Write[i] != tape.Read(m.carriages[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// execError wraps err into MultiExecError describing the current configuration.
+func (m *MultiMachine) execError(err error) *MultiExecError {
+	e := &MultiExecError{
+		Err:        err,
+		State:      m.state,
+		Carriages:  append([]int(nil), m.carriages...),
+		Steps:      m.steps,
+		Tapes:      make([]string, len(m.tapes)),
+		TapeStarts: make([]int, len(m.tapes)),
+	}
+
+	symbols := make([]rune, len(m.tapes))
+
+	for i, tape := range m.tapes {
+		symbols[i] = tape.Read(m.carriages[i])
+		e.Tapes[i] = tape.Window(m.carriages[i], tapeExcerptRadius)
+		e.TapeStarts[i] = m.carriages[i] - tapeExcerptRadius
+	}
+
+	e.Symbols = string(symbols)
+
+	return e
+}
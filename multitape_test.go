@@ -0,0 +1,307 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// copyProgram copies the first tape to the second one.
+func copyProgram() turing.MultiProgram {
+	return turing.MultiProgram{
+		"Q1": {
+			"1_": {NextState: "Q1", Write: []rune("11"), Move: []turing.Direction{turing.Right, turing.Right}},
+			"0_": {NextState: "Q1", Write: []rune("00"), Move: []turing.Direction{turing.Right, turing.Right}},
+			"__": {NextState: "Q0", Write: []rune("__"), Move: []turing.Direction{turing.Stay, turing.Stay}},
+		},
+	}
+}
+
+func TestMultiMachine_Exec(t *testing.T) {
+	t.Parallel()
+
+	machine, err := turing.NewMultiMachine(
+		2,
+		copyProgram(),
+		turing.WithAlphabet("01"),
+		turing.WithBlank('_'),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(20),
+		turing.WithMaxSteps(20),
+	)
+	require.NoError(t, err)
+
+	input := turing.TapeFromString("1011", 0)
+
+	tapes, err := machine.Exec([]int{0, 5}, []*turing.Tape{input, nil})
+	require.NoError(t, err)
+	require.Len(t, tapes, 2)
+	assert.Equal(t, "1011", tapes[0].String())
+
+	s, pos := tapes[1].Trimmed()
+	assert.Equal(t, "1011", s)
+	assert.Equal(t, 5, pos)
+
+	// the input is left untouched
+	assert.Equal(t, ' ', input.Blank())
+}
+
+func TestMultiMachine_Run_Compare(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		a, b  string
+		kind  turing.HaltKind
+		steps uint
+	}{
+		{name: "equal", a: "abba", b: "abba", kind: turing.Accept, steps: 5},
+		{name: "different", a: "abba", b: "abab", kind: turing.Reject, steps: 2},
+		{name: "prefix", a: "ab", b: "abb", kind: turing.Reject, steps: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// accepts equal words on both tapes, rejects otherwise
+			machine, err := turing.NewMultiMachine(
+				2,
+				turing.MultiProgram{
+					"Q1": {
+						"aa": {NextState: "Q1", Write: []rune("aa"), Move: []turing.Direction{turing.Right, turing.Right}},
+						"bb": {NextState: "Q1", Write: []rune("bb"), Move: []turing.Direction{turing.Right, turing.Right}},
+						"  ": {NextState: "QA", Write: []rune("  "), Move: []turing.Direction{turing.Stay, turing.Stay}},
+					},
+				},
+				turing.WithAlphabet("ab"),
+				turing.WithStartState("Q1"),
+				turing.WithHaltingState("QA", turing.Accept),
+				turing.WithMaxTapeLength(20),
+				turing.WithUndefinedTransition(turing.UndefinedReject),
+			)
+			require.NoError(t, err)
+
+			result, err := machine.Run([]int{0, 0}, []*turing.Tape{
+				turing.TapeFromString(tc.a, 0),
+				turing.TapeFromString(tc.b, 0),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.kind, result.Kind)
+			assert.Equal(t, tc.steps, result.Steps)
+		})
+	}
+}
+
+func TestMultiMachine_Exec_Errors(t *testing.T) {
+	t.Parallel()
+
+	newMachine := func(program turing.MultiProgram, maxTapeLength, maxSteps uint) *turing.MultiMachine {
+		machine, err := turing.NewMultiMachine(
+			2,
+			program,
+			turing.WithAlphabet("01"),
+			turing.WithBlank('_'),
+			turing.WithStartState("Q1"),
+			turing.WithTerminalState("Q0"),
+			turing.WithMaxTapeLength(maxTapeLength),
+			turing.WithMaxSteps(maxSteps),
+		)
+		require.NoError(t, err)
+
+		return machine
+	}
+
+	input := []*turing.Tape{turing.TapeFromString("1111", 0), nil}
+
+	_, err := newMachine(copyProgram(), 3, 0).Exec([]int{0, 0}, input)
+	require.ErrorIs(t, err, turing.ErrTapeOver)
+
+	_, err = newMachine(copyProgram(), 20, 2).Exec([]int{0, 0}, input)
+	require.ErrorIs(t, err, turing.ErrStepsExceeded)
+
+	_, err = newMachine(copyProgram(), 20, 0).Exec([]int{0, 0}, []*turing.Tape{turing.TapeFromString("12", 0), nil})
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+
+	_, err = newMachine(copyProgram(), 20, 0).Exec([]int{0, 0}, []*turing.Tape{input[0], input[0]})
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+	assert.Contains(t, err.Error(), `symbols "11"`)
+
+	var execErr *turing.MultiExecError
+	require.True(t, errors.As(err, &execErr))
+	assert.Equal(t, "Q1", execErr.State)
+	assert.Equal(t, "11", execErr.Symbols)
+	assert.Equal(t, []int{0, 0}, execErr.Carriages)
+	assert.Equal(t, uint(0), execErr.Steps)
+	assert.Equal(t, []string{"__________1111_______", "__________1111_______"}, execErr.Tapes)
+	assert.Equal(t, []int{-10, -10}, execErr.TapeStarts)
+
+	_, err = newMachine(copyProgram(), 20, 0).Exec([]int{0}, input)
+	require.ErrorIs(t, err, turing.ErrTapesMismatch)
+
+	loop := turing.MultiProgram{
+		"Q1": {"__": {NextState: "Q1", Write: []rune("__"), Move: []turing.Direction{turing.Stay, turing.Stay}}},
+	}
+
+	_, err = newMachine(loop, 20, 0).Exec([]int{0, 0}, []*turing.Tape{nil, nil})
+	require.ErrorIs(t, err, turing.ErrInfiniteLoop)
+}
+
+func TestMultiMachine_ExecCtx_ContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	machine, err := turing.NewMultiMachine(
+		2,
+		turing.MultiProgram{
+			"Q1": {"__": {NextState: "Q1", Write: []rune("__"), Move: []turing.Direction{turing.Right, turing.Left}}},
+		},
+		turing.WithBlank('_'),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tapes, err := machine.ExecCtx(ctx, []int{0, 0}, []*turing.Tape{nil, nil})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, tapes)
+}
+
+func TestMultiProgram_Validate(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+	moves := []turing.Direction{turing.Right, turing.Right}
+
+	testCases := []struct {
+		name    string
+		program turing.MultiProgram
+		err     error
+	}{
+		{
+			name:    "valid",
+			program: turing.MultiProgram{"Q1": {"1 ": {NextState: "Q0", Write: []rune("11"), Move: moves}}},
+		},
+		{
+			name:    "short key",
+			program: turing.MultiProgram{"Q1": {"1": {NextState: "Q0", Write: []rune("11"), Move: moves}}},
+			err:     turing.ErrTapesMismatch,
+		},
+		{
+			name:    "short write",
+			program: turing.MultiProgram{"Q1": {"11": {NextState: "Q0", Write: []rune("1"), Move: moves}}},
+			err:     turing.ErrTapesMismatch,
+		},
+		{
+			name: "invalid move",
+			program: turing.MultiProgram{"Q1": {"11": {
+				NextState: "Q0", Write: []rune("11"), Move: []turing.Direction{turing.Right, 2},
+			}}},
+			err: turing.ErrInvalidMoveDirection,
+		},
+		{
+			name:    "unexpected write",
+			program: turing.MultiProgram{"Q1": {"11": {NextState: "Q0", Write: []rune("1a"), Move: moves}}},
+			err:     turing.ErrUnexpectedSymbol,
+		},
+		{
+			name:    "state not found",
+			program: turing.MultiProgram{"Q1": {"11": {NextState: "Q2", Write: []rune("11"), Move: moves}}},
+			err:     turing.ErrStateNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.program.Validate(2, alphabet, "Q0")
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestMultiProgram_Validate_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.MultiProgram{
+		"Q2": {"11": {NextState: "Q9", Write: []rune("11"), Move: []turing.Direction{turing.Stay, turing.Stay}}},
+		"Q1": {
+			"1":  {NextState: "Q0", Write: []rune("11"), Move: []turing.Direction{turing.Stay, turing.Stay}},
+			"x ": {NextState: "Q0", Write: []rune("1y"), Move: []turing.Direction{turing.Stay, 3}},
+		},
+	}
+
+	expected := strings.Join([]string{
+		`tapes count mismatch: 1 symbols for state "Q1", symbols "1"`,
+		`unexpected symbol: 'x' read by state "Q1"`,
+		`invalid move direction: 3 for state "Q1", symbols "x "`,
+		`unexpected symbol: 'y' for state "Q1", symbols "x "`,
+		`state not found: "Q9" for state "Q2", symbols "11"`,
+	}, "\n")
+
+	// the order does not depend on the map iteration order
+	for range 10 {
+		require.EqualError(t, program.Validate(2, alphabet, "Q0"), expected)
+	}
+}
+
+func TestNewMultiMachine_UnsupportedOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []turing.Option{
+		turing.WithTracer(turing.NewRecorder(0)),
+		turing.WithLoopDetection(turing.DetectCycles),
+		turing.WithTapeMode(turing.OneWayStay),
+	} {
+		_, err := turing.NewMultiMachine(
+			2,
+			copyProgram(),
+			turing.WithBlank('_'),
+			turing.WithStartState("Q1"),
+			turing.WithTerminalState("Q0"),
+			turing.WithMaxTapeLength(10),
+			opt,
+		)
+		require.ErrorIs(t, err, turing.ErrUnsupportedOption)
+	}
+}
+
+func TestMultiProgram_ValidateStart(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'0': {}, '1': {}, '_': {}}
+
+	program := copyProgram()
+
+	require.NoError(t, program.ValidateStart(2, alphabet, "Q1", "Q0"))
+	require.NoError(t, program.ValidateStart(2, alphabet, "Q0", "Q0"))
+	require.ErrorIs(t, program.ValidateStart(2, alphabet, "QX", "Q0"), turing.ErrStateNotFound)
+
+	machine, err := turing.NewMultiMachine(
+		2,
+		program,
+		turing.WithAlphabet("01"),
+		turing.WithBlank('_'),
+		turing.WithStartState("QX"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+	)
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+	assert.Nil(t, machine)
+}
@@ -0,0 +1,319 @@
+package turing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrConfigurationsExceeded is returned when a nondeterministic machine explores
+// more configurations than allowed.
+var ErrConfigurationsExceeded = errors.New("configurations exceeded")
+
+// NondeterministicProgram for nondeterministic Turing machine:
+// program[Q1][A] lists all the transitions the machine may apply.
+type NondeterministicProgram map[string]map[rune][]Transition
+
+// Validate checks move, write and next state fields for every transition of program,
+// and that every transition is keyed on a symbol of the alphabet. Any of the halting states
+// is a valid next state. All the problems found are joined into the returned error,
+// ordered by state and symbol.
+func (np NondeterministicProgram) Validate(alphabet map[rune]struct{}, haltingStates ...string) error {
+	c := newProgramCheck(alphabet, haltingStates, func(state string) bool {
+		_, ok := np[state]
+
+		return ok
+	})
+
+	for _, state := range sortedKeys(np) {
+		for _, symbol := range sortedKeys(np[state]) {
+			c.read(symbol, state)
+
+			for i, transition := range np[state][symbol] {
+				at := fmt.Sprintf("state %q, symbol %q, transition %d", state, symbol, i)
+
+				c.move(transition.Move, isMove(transition.Move), at)
+				c.write(transition.Write, at)
+				c.next(transition.NextState, at)
+			}
+		}
+	}
+
+	return c.err()
+}
+
+// ValidateStart is Validate also checking that the start state is defined in program
+// or is one of the halting states.
+func (np NondeterministicProgram) ValidateStart(alphabet map[rune]struct{}, start string, haltingStates ...string) error {
+	return validateStart(np, start, haltingStates, np.Validate(alphabet, haltingStates...))
+}
+
+// NondeterministicMachine is a Turing machine which may apply any of several transitions
+// for the same state and symbol. It accepts the input if any branch of the computation
+// reaches an accepting state. If no halting state is configured as Accept, the neutral
+// halting states accept. A branch without transition for its state and symbol,
+// or reaching another halting state, rejects.
+type NondeterministicMachine struct {
+	startState string
+
+	haltingStates map[string]HaltKind
+
+	// halting states accepting the input
+	accepting map[string]struct{}
+
+	alphabet map[rune]struct{}
+
+	// symbol of the empty cells, always part of the alphabet
+	blank rune
+
+	program NondeterministicProgram
+
+	// limits applied to every branch
+	maxTapeLength uint
+	maxSteps      uint
+
+	// limit of the whole search
+	maxConfigurations uint
+}
+
+// SearchResult describes a finished search of the configuration tree.
+type SearchResult struct {
+	// Accepted reports whether any branch reached an accepting state.
+	Accepted bool
+
+	// State is the accepting state reached, empty if the input was not accepted.
+	State string
+
+	// Tape is the final tape of the accepting branch, nil if the input was not accepted.
+	Tape *Tape
+
+	// Path is the steps of the accepting branch from the start configuration.
+	Path []StepRecord
+
+	// Configurations is the number of explored configurations.
+	Configurations uint
+}
+
+// NewNondeterministicMachine creates a new nondeterministic Turing machine running the program
+// configured by the given options. The alphabet, blank, start state, halting states,
+// max tape length, max steps and max configurations options are used, the tracer,
+// loop detection and tape mode options fail with ErrUnsupportedOption.
+func NewNondeterministicMachine(program NondeterministicProgram, opts ...Option) (*NondeterministicMachine, error) {
+	c := newConfig(opts)
+	a := c.symbols()
+
+	if err := c.checkUnsupported("nondeterministic machine"); err != nil {
+		return nil, err
+	}
+
+	halting, err := c.check()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := program.ValidateStart(a, c.startState, halting...); err != nil {
+		return nil, err
+	}
+
+	accepting := make(map[string]struct{})
+
+	for state, kind := range c.haltingStates {
+		if kind == Accept {
+			accepting[state] = struct{}{}
+		}
+	}
+
+	if len(accepting) == 0 {
+		for state, kind := range c.haltingStates {
+			if kind == Neutral {
+				accepting[state] = struct{}{}
+			}
+		}
+	}
+
+	return &NondeterministicMachine{
+		startState:        c.startState,
+		haltingStates:     c.haltingStates,
+		accepting:         accepting,
+		alphabet:          a,
+		blank:             c.blank,
+		program:           program,
+		maxTapeLength:     c.maxTapeLength,
+		maxSteps:          c.maxSteps,
+		maxConfigurations: c.maxConfigurations,
+	}, nil
+}
+
+// node is a configuration in the tree explored by the search.
+type node struct {
+	state    string
+	carriage int
+	tape     *Tape
+	steps    uint
+
+	// the configuration this one was reached from and the step made, nil for the root
+	parent *node
+	record StepRecord
+}
+
+// path returns the steps from the root to n.
+func (n *node) path() []StepRecord {
+	path := make([]StepRecord, n.steps)
+	for ; n.parent != nil; n = n.parent {
+		path[n.steps-1] = n.record
+	}
+
+	return path
+}
+
+// execError wraps err into ExecError describing the configuration,
+// Steps is the depth of the configuration in the tree.
+func (n *node) execError(err error) *ExecError {
+	return &ExecError{
+		Err:       err,
+		State:     n.state,
+		Symbol:    n.tape.Read(n.carriage),
+		Carriage:  n.carriage,
+		Steps:     n.steps,
+		Tape:      n.tape.Window(n.carriage, tapeExcerptRadius),
+		TapeStart: n.carriage - tapeExcerptRadius,
+	}
+}
+
+// key identifies the configuration, blank cells are not taken into account.
+func (n *node) key() string {
+	s, pos := n.tape.Trimmed()
+
+	var sb strings.Builder
+
+	sb.WriteString(n.state)
+	sb.WriteByte(0)
+	sb.WriteString(strconv.Itoa(n.carriage))
+	sb.WriteByte(0)
+	sb.WriteString(strconv.Itoa(pos))
+	sb.WriteByte(0)
+	sb.WriteString(s)
+
+	return sb.String()
+}
+
+// Search is SearchCtx with the background context.
+func (m *NondeterministicMachine) Search(carriage int, input *Tape) (SearchResult, error) {
+	return m.SearchCtx(context.Background(), carriage, input)
+}
+
+// SearchCtx explores the configuration tree breadth-first starting from the input tape
+// with the carriage at the given position, so the accepting path found is the shortest one.
+// Configurations met before are not explored again. The input is not modified.
+//
+// Branches exceeding the max tape length or the max steps are cut off. If the input
+// is not accepted and some branch was cut off, the result is unknown and an ExecError
+// wrapping ErrTapeOver or ErrStepsExceeded describes the first configuration cut off.
+// Other failures are ExecError values describing the configuration being explored.
+func (m *NondeterministicMachine) SearchCtx(ctx context.Context, carriage int, input *Tape) (SearchResult, error) {
+	root := &node{
+		state:    m.startState,
+		carriage: carriage,
+		tape:     input.withBlank(m.blank),
+	}
+
+	var (
+		result SearchResult
+		cutOff error
+	)
+
+	visited := map[string]struct{}{root.key(): {}}
+	queue := []*node{root}
+
+	for len(queue) > 0 {
+		if ctx.Err() != nil {
+			return result, ctx.Err() //nolint:wrapcheck
+		}
+
+		n := queue[0]
+		queue[0] = nil
+		queue = queue[1:]
+
+		result.Configurations++
+
+		if m.maxConfigurations > 0 && result.Configurations > m.maxConfigurations {
+			return result, n.execError(fmt.Errorf("%w: %d configurations", ErrConfigurationsExceeded, m.maxConfigurations))
+		}
+
+		if _, ok := m.accepting[n.state]; ok {
+			result.Accepted = true
+			result.State = n.state
+			result.Tape = n.tape
+			result.Path = n.path()
+
+			return result, nil
+		}
+
+		if _, ok := m.haltingStates[n.state]; ok {
+			continue
+		}
+
+		sym := n.tape.Read(n.carriage)
+		if _, ok := m.alphabet[sym]; !ok {
+			return result, n.execError(ErrUnexpectedSymbol)
+		}
+
+		for _, transition := range m.program[n.state][sym] {
+			child := &node{
+				state:    transition.NextState,
+				carriage: n.carriage + int(transition.Move),
+				tape:     n.tape.Clone(),
+				steps:    n.steps + 1,
+				parent:   n,
+				record: StepRecord{
+					Step:           n.steps + 1,
+					State:          n.state,
+					Symbol:         sym,
+					Transition:     transition,
+					CarriageBefore: n.carriage,
+					CarriageAfter:  n.carriage + int(transition.Move),
+				},
+			}
+
+			child.tape.Write(n.carriage, transition.Write)
+
+			key := child.key()
+			if _, ok := visited[key]; ok {
+				continue
+			}
+
+			visited[key] = struct{}{}
+
+			if err := m.limit(child); err != nil {
+				if cutOff == nil {
+					cutOff = err
+				}
+
+				continue
+			}
+
+			queue = append(queue, child)
+		}
+	}
+
+	return result, cutOff
+}
+
+// limit returns the error for the configuration exceeding the branch limits.
+func (m *NondeterministicMachine) limit(n *node) error {
+	if _, ok := m.accepting[n.state]; ok {
+		return nil
+	}
+
+	if uint(n.tape.Len()) >= m.maxTapeLength {
+		return n.execError(ErrTapeOver)
+	}
+
+	if m.maxSteps > 0 && n.steps >= m.maxSteps {
+		return n.execError(ErrStepsExceeded)
+	}
+
+	return nil
+}
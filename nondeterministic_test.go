@@ -0,0 +1,298 @@
+package turing_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// containsProgram accepts words having "bb" inside guessing where it starts.
+func containsProgram() turing.NondeterministicProgram {
+	return turing.NondeterministicProgram{
+		"Q1": {
+			'a': {{NextState: "Q1", Move: turing.Right, Write: 'a'}},
+			'b': {
+				{NextState: "Q1", Move: turing.Right, Write: 'b'},
+				{NextState: "Q2", Move: turing.Right, Write: 'b'},
+			},
+		},
+		"Q2": {
+			'b': {{NextState: "QA", Move: turing.Stay, Write: 'b'}},
+		},
+	}
+}
+
+func newContainsMachine(t *testing.T, opts ...turing.Option) *turing.NondeterministicMachine {
+	t.Helper()
+
+	machine, err := turing.NewNondeterministicMachine(
+		containsProgram(),
+		append([]turing.Option{
+			turing.WithAlphabet("ab"),
+			turing.WithStartState("Q1"),
+			turing.WithHaltingState("QA", turing.Accept),
+			turing.WithMaxTapeLength(100),
+		}, opts...)...,
+	)
+	require.NoError(t, err)
+
+	return machine
+}
+
+func TestNondeterministicMachine_Search(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		accepted bool
+		path     int
+	}{
+		{name: "blank between", input: "abab bb", accepted: false},
+		{name: "inside", input: "aabba", accepted: true, path: 4},
+		{name: "end", input: "ababb", accepted: true, path: 5},
+		{name: "rejected", input: "ababa", accepted: false},
+		{name: "empty", input: "", accepted: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := turing.TapeFromString(tc.input, 0)
+
+			result, err := newContainsMachine(t).Search(0, input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.accepted, result.Accepted)
+			assert.Len(t, result.Path, tc.path)
+			assert.Equal(t, tc.input, input.String())
+
+			if !tc.accepted {
+				assert.Nil(t, result.Tape)
+				return
+			}
+
+			assert.Equal(t, "QA", result.State)
+			assert.Equal(t, tc.input, result.Tape.String())
+
+			// the path is consistent step by step
+			for i, record := range result.Path {
+				assert.Equal(t, uint(i+1), record.Step)
+
+				if i > 0 {
+					assert.Equal(t, result.Path[i-1].Transition.NextState, record.State)
+					assert.Equal(t, result.Path[i-1].CarriageAfter, record.CarriageBefore)
+				}
+			}
+
+			assert.Equal(t, "Q2", result.Path[len(result.Path)-1].State)
+		})
+	}
+}
+
+func TestNondeterministicMachine_Search_NeutralAccepts(t *testing.T) {
+	t.Parallel()
+
+	// guesses a symbol to write, only '1' leads to the terminal state
+	machine, err := turing.NewNondeterministicMachine(
+		turing.NondeterministicProgram{
+			"Q1": {' ': {
+				{NextState: "Q2", Move: turing.Stay, Write: '0'},
+				{NextState: "Q2", Move: turing.Stay, Write: '1'},
+			}},
+			"Q2": {'1': {{NextState: "Q0", Move: turing.Right, Write: '1'}}},
+		},
+		turing.WithAlphabet("01"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Search(0, &turing.Tape{})
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+	assert.Equal(t, "Q0", result.State)
+	assert.Equal(t, "1", result.Tape.String())
+	require.Len(t, result.Path, 2)
+	assert.Equal(t, '1', result.Path[0].Transition.Write)
+}
+
+func TestNondeterministicMachine_Search_Limits(t *testing.T) {
+	t.Parallel()
+
+	input := turing.TapeFromString("abababababbb", 0)
+
+	_, err := newContainsMachine(t, turing.WithMaxConfigurations(5)).Search(0, input)
+	require.ErrorIs(t, err, turing.ErrConfigurationsExceeded)
+
+	var execErr *turing.ExecError
+	require.True(t, errors.As(err, &execErr))
+	assert.Equal(t, uint(4), execErr.Steps)
+
+	_, err = newContainsMachine(t, turing.WithMaxSteps(5)).Search(0, input)
+	require.ErrorIs(t, err, turing.ErrStepsExceeded)
+
+	// an accepting branch found before the limit is enough
+	result, err := newContainsMachine(t, turing.WithMaxSteps(5)).Search(0, turing.TapeFromString("bbabababab", 0))
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+
+	// writes forever either to the left or to the right
+	machine, err := turing.NewNondeterministicMachine(
+		turing.NondeterministicProgram{
+			"Q1": {' ': {
+				{NextState: "Q1", Move: turing.Left, Write: '1'},
+				{NextState: "Q1", Move: turing.Right, Write: '1'},
+			}},
+		},
+		turing.WithAlphabet("1"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(5),
+	)
+	require.NoError(t, err)
+
+	_, err = machine.Search(0, &turing.Tape{})
+	require.ErrorIs(t, err, turing.ErrTapeOver)
+	require.True(t, errors.As(err, &execErr))
+	assert.Equal(t, uint(5), execErr.Steps)
+	assert.Equal(t, "Q1", execErr.State)
+
+	_, err = machine.Search(0, turing.TapeFromString("x", 0))
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+	require.True(t, errors.As(err, &execErr))
+	assert.Equal(t, 'x', execErr.Symbol)
+	assert.Equal(t, 0, execErr.Carriage)
+}
+
+func TestNondeterministicMachine_Search_Loop(t *testing.T) {
+	t.Parallel()
+
+	// moves back and forth forever, the repeated configurations are not explored again
+	machine, err := turing.NewNondeterministicMachine(
+		turing.NondeterministicProgram{
+			"Q1": {' ': {
+				{NextState: "Q2", Move: turing.Right, Write: ' '},
+				{NextState: "Q1", Move: turing.Stay, Write: ' '},
+			}},
+			"Q2": {' ': {{NextState: "Q1", Move: turing.Left, Write: ' '}}},
+		},
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(5),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Search(0, &turing.Tape{})
+	require.NoError(t, err)
+	assert.False(t, result.Accepted)
+	assert.Equal(t, uint(2), result.Configurations)
+}
+
+func TestNondeterministicMachine_SearchCtx_ContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newContainsMachine(t).SearchCtx(ctx, 0, turing.TapeFromString("abba", 0))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNondeterministicProgram_Validate(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	err := turing.NondeterministicProgram{
+		"Q1": {'1': {
+			{NextState: "Q0", Move: turing.Right, Write: '1'},
+			{NextState: "Q2", Move: turing.Right, Write: '1'},
+		}},
+	}.Validate(alphabet, "Q0")
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+
+	err = turing.NondeterministicProgram{
+		"Q1": {'1': {
+			{NextState: "Q0", Move: turing.Right, Write: '1'},
+			{NextState: "Q0", Move: 3, Write: '1'},
+		}},
+	}.Validate(alphabet, "Q0")
+	require.ErrorIs(t, err, turing.ErrInvalidMoveDirection)
+
+	err = turing.NondeterministicProgram{
+		"Q1": {'1': {{NextState: "Q0", Move: turing.Right, Write: 'x'}}},
+	}.Validate(alphabet, "Q0")
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+}
+
+func TestNondeterministicProgram_Validate_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.NondeterministicProgram{
+		"Q2": {'1': {{NextState: "Q9", Move: turing.Stay, Write: '1'}}},
+		"Q1": {
+			'x': {{NextState: "Q0", Move: turing.Stay, Write: '1'}},
+			' ': {
+				{NextState: "Q0", Move: turing.Stay, Write: '1'},
+				{NextState: "Q0", Move: 3, Write: 'y'},
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		`invalid move direction: 3 for state "Q1", symbol ' ', transition 1`,
+		`unexpected symbol: 'y' for state "Q1", symbol ' ', transition 1`,
+		`unexpected symbol: 'x' read by state "Q1"`,
+		`state not found: "Q9" for state "Q2", symbol '1', transition 0`,
+	}, "\n")
+
+	// the order does not depend on the map iteration order
+	for range 10 {
+		require.EqualError(t, program.Validate(alphabet, "Q0"), expected)
+	}
+}
+
+func TestNewNondeterministicMachine_UnsupportedOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := turing.NewNondeterministicMachine(
+		containsProgram(),
+		turing.WithAlphabet("ab"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+		turing.WithLoopDetection(turing.DetectCycles),
+	)
+	require.ErrorIs(t, err, turing.ErrUnsupportedOption)
+}
+
+func TestNondeterministicProgram_ValidateStart(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'a': {}, 'b': {}, ' ': {}}
+
+	program := containsProgram()
+
+	require.NoError(t, program.ValidateStart(alphabet, "Q1", "QA"))
+	require.NoError(t, program.ValidateStart(alphabet, "QA", "QA"))
+	require.ErrorIs(t, program.ValidateStart(alphabet, "QX", "QA"), turing.ErrStateNotFound)
+
+	machine, err := turing.NewNondeterministicMachine(
+		program,
+		turing.WithAlphabet("ab"),
+		turing.WithStartState("QX"),
+		turing.WithHaltingState("QA", turing.Accept),
+		turing.WithMaxTapeLength(100),
+	)
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+	assert.Nil(t, machine)
+}
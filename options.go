@@ -0,0 +1,248 @@
+package turing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnsupportedOption is returned when a machine is given an option it does not support.
+var ErrUnsupportedOption = errors.New("unsupported option")
+
+// Option configures a machine created by New.
+type Option func(*config)
+
+// config collects the machine configuration set by options.
+type config struct {
+	alphabet      string
+	blank         rune
+	startState    string
+	haltingStates map[string]HaltKind
+	maxTapeLength uint
+	maxSteps      uint
+	tracer        Tracer
+	undefined     UndefinedTransition
+	detection     LoopDetection
+	tapeMode      TapeMode
+
+	// used by nondeterministic machines only
+	maxConfigurations uint
+
+	// used by grid machines only
+	maxArea uint
+}
+
+// WithAlphabet sets the alphabet, "ABC" for example. The blank symbol is always included.
+func WithAlphabet(alphabet string) Option {
+	return func(c *config) {
+		c.alphabet = alphabet
+	}
+}
+
+// WithBlank sets the blank symbol, DefaultBlank is used by default.
+func WithBlank(blank rune) Option {
+	return func(c *config) {
+		c.blank = blank
+	}
+}
+
+// WithStartState sets the state the algorithm will start from, Q1 for example.
+func WithStartState(state string) Option {
+	return func(c *config) {
+		c.startState = state
+	}
+}
+
+// WithTerminalState adds the state the algorithm will terminate in, Q0 for example.
+// It is the same as WithHaltingState(state, Neutral).
+func WithTerminalState(state string) Option {
+	return WithHaltingState(state, Neutral)
+}
+
+// WithHaltingState adds a halting state of the given kind, it can be used several times.
+func WithHaltingState(state string, kind HaltKind) Option {
+	return func(c *config) {
+		c.haltingStates[state] = kind
+	}
+}
+
+// WithMaxTapeLength sets the maximum number of the used tape cells, it must be positive.
+func WithMaxTapeLength(length uint) Option {
+	return func(c *config) {
+		c.maxTapeLength = length
+	}
+}
+
+// WithMaxSteps sets the maximum number of steps, 0 disables the constraint and is the default.
+func WithMaxSteps(steps uint) Option {
+	return func(c *config) {
+		c.maxSteps = steps
+	}
+}
+
+// WithTracer sets the tracer receiving a record for every step, see Machine.SetTracer.
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// WithUndefinedTransition selects the behaviour on undefined transitions, see UndefinedTransition.
+func WithUndefinedTransition(u UndefinedTransition) Option {
+	return func(c *config) {
+		c.undefined = u
+	}
+}
+
+// WithLoopDetection enables additional infinite loop checks, see LoopDetection.
+func WithLoopDetection(d LoopDetection) Option {
+	return func(c *config) {
+		c.detection = d
+	}
+}
+
+// WithTapeMode selects whether the tape is bounded on the left, see TapeMode.
+func WithTapeMode(mode TapeMode) Option {
+	return func(c *config) {
+		c.tapeMode = mode
+	}
+}
+
+// WithMaxConfigurations sets the maximum number of configurations a nondeterministic
+// machine explores, 0 disables the constraint and is the default.
+func WithMaxConfigurations(n uint) Option {
+	return func(c *config) {
+		c.maxConfigurations = n
+	}
+}
+
+// WithMaxArea sets the maximum area of the used region of a grid machine, it is required
+// by grid machines like the max tape length is required by one-dimensional ones.
+func WithMaxArea(area uint) Option {
+	return func(c *config) {
+		c.maxArea = area
+	}
+}
+
+// newConfig applies the options over the defaults.
+func newConfig(opts []Option) config {
+	c := config{
+		blank:         DefaultBlank,
+		haltingStates: make(map[string]HaltKind),
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// symbols returns the alphabet with the blank symbol included.
+func (c *config) symbols() map[rune]struct{} {
+	a := make(map[rune]struct{}, len(c.alphabet)+1)
+	for _, sym := range c.alphabet {
+		a[sym] = struct{}{}
+	}
+
+	a[c.blank] = struct{}{}
+
+	return a
+}
+
+// check validates the required parameters and returns the sorted halting states.
+func (c *config) check() ([]string, error) {
+	halting, err := c.checkStates()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.maxTapeLength == 0 {
+		return nil, ErrInvalidMaxTapeLength
+	}
+
+	return halting, nil
+}
+
+// checkUnsupported returns ErrUnsupportedOption if an option supported by Machine only is set
+// for another kind of machine: the tracer, the loop detection or the tape mode.
+func (c *config) checkUnsupported(machine string) error {
+	switch {
+	case c.tracer != nil:
+		return fmt.Errorf("%w: tracer for %s", ErrUnsupportedOption, machine)
+	case c.detection != 0:
+		return fmt.Errorf("%w: loop detection for %s", ErrUnsupportedOption, machine)
+	case c.tapeMode != TwoWay:
+		return fmt.Errorf("%w: tape mode for %s", ErrUnsupportedOption, machine)
+	default:
+		return nil
+	}
+}
+
+// checkStates validates the start and halting states and returns the sorted halting states.
+func (c *config) checkStates() ([]string, error) {
+	if c.startState == "" {
+		return nil, ErrStartStateEmpty
+	}
+
+	if len(c.haltingStates) == 0 {
+		return nil, ErrTerminalStateEmpty
+	}
+
+	halting := make([]string, 0, len(c.haltingStates))
+	for state := range c.haltingStates {
+		if state == "" {
+			return nil, ErrTerminalStateEmpty
+		}
+
+		halting = append(halting, state)
+	}
+
+	sort.Strings(halting)
+
+	return halting, nil
+}
+
+// New creates a new Turing machine running the program configured by the given options.
+// The start state, at least one halting state and the max tape length are required.
+func New(program Program, opts ...Option) (*Machine, error) {
+	c := newConfig(opts)
+	a := c.symbols()
+
+	halting, err := c.check()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := program.ValidateStart(a, c.startState, halting...); err != nil {
+		return nil, err
+	}
+
+	compiled := compile(program, a, append([]string{c.startState}, halting...)...)
+
+	m := &Machine{
+		tape:          NewTape(c.blank),
+		startState:    c.startState,
+		haltingStates: c.haltingStates,
+		alphabet:      a,
+		blank:         c.blank,
+		program:       program,
+		compiled:      compiled,
+		start:         compiled.ids[c.startState],
+		halting:       make([]bool, len(compiled.states)),
+		kinds:         make([]HaltKind, len(compiled.states)),
+		maxTapeLength: c.maxTapeLength,
+		maxSteps:      c.maxSteps,
+		tracer:        c.tracer,
+		undefined:     c.undefined,
+		detection:     c.detection,
+		tapeMode:      c.tapeMode,
+	}
+
+	for state, kind := range c.haltingStates {
+		m.halting[compiled.ids[state]] = true
+		m.kinds[compiled.ids[state]] = kind
+	}
+
+	return m, nil
+}
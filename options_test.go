@@ -0,0 +1,149 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			'_': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	recorder := turing.NewRecorder(0)
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithBlank('_'),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+		turing.WithMaxSteps(10),
+		turing.WithTracer(recorder),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("11", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "111", result.Tape.String())
+	assert.Equal(t, '_', result.Tape.Blank())
+	assert.Len(t, recorder.Records(), 3)
+}
+
+func TestNew_HaltingStates(t *testing.T) {
+	t.Parallel()
+
+	machine, err := turing.New(
+		turing.Program{
+			"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+		},
+		turing.WithAlphabet("1"),
+		turing.WithStartState("Q1"),
+		turing.WithHaltingState("QA", turing.Accept),
+		turing.WithHaltingState("QR", turing.Reject),
+		turing.WithMaxTapeLength(10),
+		turing.WithUndefinedTransition(turing.UndefinedReject),
+	)
+	require.NoError(t, err)
+
+	result, err := machine.Run(0, turing.TapeFromString("11", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "Q1", result.State)
+	assert.Equal(t, turing.Reject, result.Kind)
+}
+
+func TestNew_Required(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: ' '}},
+	}
+
+	tt := []struct {
+		name string
+		opts []turing.Option
+		err  error
+	}{
+		{
+			name: "return err without start state",
+			opts: []turing.Option{
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(10),
+			},
+			err: turing.ErrStartStateEmpty,
+		},
+		{
+			name: "return err without halting states",
+			opts: []turing.Option{
+				turing.WithStartState("Q1"),
+				turing.WithMaxTapeLength(10),
+			},
+			err: turing.ErrTerminalStateEmpty,
+		},
+		{
+			name: "return err without max tape length",
+			opts: []turing.Option{
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+			},
+			err: turing.ErrInvalidMaxTapeLength,
+		},
+		{
+			name: "max steps are optional",
+			opts: []turing.Option{
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(10),
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := turing.New(program, tc.opts...)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestNewMachine_Compatible(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		10,
+		20,
+	)
+	require.NoError(t, err)
+
+	newMachine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(10),
+		turing.WithMaxSteps(20),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, machine, newMachine)
+}
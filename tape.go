@@ -0,0 +1,410 @@
+package turing
+
+import (
+	"maps"
+	"strings"
+)
+
+// Tape is an infinite tape backed by a contiguous slice that grows in both directions.
+// Cells which were never written hold the blank symbol.
+// Like the map tape used before, it keeps track of the written cells: Len and Map
+// take only them into account, not the blank cells between them.
+// Written cells lying too far apart to be held by the slice are kept in a map.
+// The zero value is an empty tape with DefaultBlank ready to use.
+type Tape struct {
+	//           origin
+	//             ↓
+	// [ ][ ][1][1][+][1][ ][ ]
+	//       ^lo         ^hi
+	// buf holds the cells with some spare room on both sides,
+	// the used part of the tape is buf[lo:hi]
+	buf []rune
+
+	// written marks the cells of buf which were written
+	written []bool
+
+	// number of written cells
+	count int
+
+	// index in buf of the cell at position 0
+	origin int
+
+	// bounds of the used part of buf, hi is exclusive
+	lo, hi int
+
+	// written cells if they are too sparse for buf, nil otherwise;
+	// buf is dropped then, origin is zero and lo, hi are positions
+	sparse map[int]rune
+
+	// blank symbol, used if blankSet, DefaultBlank otherwise,
+	// so that any rune including zero can be the blank symbol
+	blank    rune
+	blankSet bool
+}
+
+// NewTape creates an empty tape with the given blank symbol.
+func NewTape(blank rune) *Tape {
+	return &Tape{blank: blank, blankSet: true}
+}
+
+// TapeFromMap creates a tape with DefaultBlank holding the given cells.
+func TapeFromMap(cells map[int]rune) *Tape {
+	t := &Tape{}
+	t.WriteMap(cells)
+
+	return t
+}
+
+// TapeFromString creates a tape with DefaultBlank holding the symbols of s,
+// the first one at the origin position. Spaces in s are blank cells.
+func TapeFromString(s string, origin int) *Tape {
+	t := &Tape{}
+	t.WriteString(origin, s)
+
+	return t
+}
+
+// Blank returns the blank symbol of the tape.
+func (t *Tape) Blank() rune {
+	if !t.blankSet {
+		return DefaultBlank
+	}
+
+	return t.blank
+}
+
+// WriteMap puts the given cells on the tape.
+func (t *Tape) WriteMap(cells map[int]rune) {
+	for pos, sym := range cells {
+		t.Write(pos, sym)
+	}
+}
+
+// WriteString puts the symbols of s on the tape, the first one at the given position.
+// Blank symbols in s leave the cells untouched.
+func (t *Tape) WriteString(pos int, s string) {
+	blank := t.Blank()
+
+	for _, sym := range s {
+		if sym != blank {
+			t.Write(pos, sym)
+		}
+
+		pos++
+	}
+}
+
+// Read returns the symbol at the given position or the blank symbol if the cell is empty.
+func (t *Tape) Read(pos int) rune {
+	i := pos + t.origin
+	if i < t.lo || i >= t.hi {
+		return t.Blank()
+	}
+
+	if t.sparse != nil {
+		if sym, ok := t.sparse[pos]; ok {
+			return sym
+		}
+
+		return t.Blank()
+	}
+
+	return t.buf[i]
+}
+
+// Write puts the symbol to the given position, growing the tape if needed.
+func (t *Tape) Write(pos int, sym rune) {
+	if t.sparse != nil {
+		t.writeSparse(pos, sym)
+
+		return
+	}
+
+	if t.lo == t.hi {
+		t.grow(pos)
+		t.lo = pos + t.origin
+		t.hi = t.lo + 1
+	}
+
+	i := pos + t.origin
+	if i < 0 || i >= len(t.buf) {
+		if t.grow(pos); t.sparse != nil {
+			t.writeSparse(pos, sym)
+
+			return
+		}
+
+		i = pos + t.origin
+	}
+
+	t.buf[i] = sym
+
+	if !t.written[i] {
+		t.written[i] = true
+		t.count++
+	}
+
+	if i < t.lo {
+		t.lo = i
+	}
+
+	if i >= t.hi {
+		t.hi = i + 1
+	}
+}
+
+// writeSparse puts the symbol to the given position of the sparse tape.
+func (t *Tape) writeSparse(pos int, sym rune) {
+	if _, ok := t.sparse[pos]; !ok {
+		t.count++
+	}
+
+	t.sparse[pos] = sym
+
+	if pos < t.lo {
+		t.lo = pos
+	}
+
+	if pos >= t.hi {
+		t.hi = pos + 1
+	}
+}
+
+// maxDenseSpan returns how many cells buf may span to hold the given
+// number of written cells, beyond that the tape becomes sparse.
+func maxDenseSpan(count int) int {
+	const (
+		minSpan = 1 << 16
+		ratio   = 16
+	)
+
+	return max(minSpan, ratio*count)
+}
+
+// grow reallocates the buffer so that pos fits in, doubling the capacity
+// to keep writes amortized O(1). If the used span would get too large for
+// the written cells, the tape becomes sparse instead.
+func (t *Tape) grow(pos int) {
+	const minSize = 16
+
+	i := pos + t.origin
+	if i >= 0 && i < len(t.buf) {
+		return
+	}
+
+	used := t.hi - t.lo
+
+	size := 2 * len(t.buf)
+	if size < minSize {
+		size = minSize
+	}
+
+	// the new used span must fit with the same amount of room on both sides
+	lo, hi := t.lo, t.hi
+	if used == 0 {
+		lo, hi = i, i+1
+	}
+
+	if i < lo {
+		lo = i
+	}
+
+	if i >= hi {
+		hi = i + 1
+	}
+
+	if hi-lo > maxDenseSpan(t.count+1) {
+		t.toSparse()
+
+		return
+	}
+
+	for size < 2*(hi-lo) {
+		size *= 2
+	}
+
+	blank := t.Blank()
+
+	buf := make([]rune, size)
+	for j := range buf {
+		buf[j] = blank
+	}
+
+	written := make([]bool, size)
+
+	// place the used span in the middle of the new buffer
+	shift := (size-(hi-lo))/2 - lo
+	if used > 0 {
+		copy(buf[t.lo+shift:], t.buf[t.lo:t.hi])
+		copy(written[t.lo+shift:], t.written[t.lo:t.hi])
+	}
+
+	t.buf = buf
+	t.written = written
+	t.origin += shift
+	t.lo += shift
+	t.hi += shift
+}
+
+// toSparse moves the written cells from buf to the sparse map.
+func (t *Tape) toSparse() {
+	t.sparse = t.Map()
+
+	t.lo -= t.origin
+	t.hi -= t.origin
+	t.origin = 0
+	t.buf = nil
+	t.written = nil
+}
+
+// each calls fn for every written cell.
+func (t *Tape) each(fn func(pos int, sym rune)) {
+	if t.sparse != nil {
+		for pos, sym := range t.sparse {
+			fn(pos, sym)
+		}
+
+		return
+	}
+
+	for i := t.lo; i < t.hi; i++ {
+		if t.written[i] {
+			fn(i-t.origin, t.buf[i])
+		}
+	}
+}
+
+// Len returns the number of written cells, blank cells between them are not counted.
+func (t *Tape) Len() int {
+	return t.count
+}
+
+// Map converts the written cells to the map format accepted and returned by Exec.
+func (t *Tape) Map() map[int]rune {
+	cells := make(map[int]rune, t.count)
+	t.each(func(pos int, sym rune) {
+		cells[pos] = sym
+	})
+
+	return cells
+}
+
+// bounds returns the positions of the leftmost used cell and the one following
+// the rightmost used cell.
+func (t *Tape) bounds() (int, int) {
+	return t.lo - t.origin, t.hi - t.origin
+}
+
+// Trimmed renders the tape to a string without leading and trailing blanks
+// and returns it with the position of its first symbol.
+// An empty string and 0 are returned for the blank tape.
+func (t *Tape) Trimmed() (string, int) {
+	blank := t.Blank()
+
+	if t.sparse != nil {
+		return t.trimmedSparse(blank)
+	}
+
+	lo, hi := t.lo, t.hi
+	for lo < hi && t.buf[lo] == blank {
+		lo++
+	}
+
+	for hi > lo && t.buf[hi-1] == blank {
+		hi--
+	}
+
+	if lo == hi {
+		return "", 0
+	}
+
+	return string(t.buf[lo:hi]), lo - t.origin
+}
+
+// trimmedSparse is Trimmed for the sparse tape.
+func (t *Tape) trimmedSparse(blank rune) (string, int) {
+	lo, hi := t.hi, t.lo
+
+	for pos, sym := range t.sparse {
+		if sym != blank {
+			lo = min(lo, pos)
+			hi = max(hi, pos+1)
+		}
+	}
+
+	if lo >= hi {
+		return "", 0
+	}
+
+	cells := make([]rune, hi-lo)
+	for i := range cells {
+		cells[i] = t.Read(lo + i)
+	}
+
+	return string(cells), lo
+}
+
+// String returns the tape content without leading and trailing blanks.
+func (t *Tape) String() string {
+	s, _ := t.Trimmed()
+
+	return s
+}
+
+// Window renders the cells from pos-radius to pos+radius inclusive.
+func (t *Tape) Window(pos, radius int) string {
+	var sb strings.Builder
+
+	for i := pos - radius; i <= pos+radius; i++ {
+		sb.WriteRune(t.Read(i))
+	}
+
+	return sb.String()
+}
+
+// Equal reports whether both tapes hold the same symbols at the same positions,
+// blank cells are not taken into account. Tapes with different blank symbols
+// are compared as rendered, so each blank only matches itself.
+func (t *Tape) Equal(other *Tape) bool {
+	s, pos := t.Trimmed()
+	otherS, otherPos := other.Trimmed()
+
+	return s == otherS && pos == otherPos
+}
+
+// Clone returns an independent copy of the tape.
+func (t *Tape) Clone() *Tape {
+	return &Tape{
+		buf:      append([]rune(nil), t.buf...),
+		written:  append([]bool(nil), t.written...),
+		count:    t.count,
+		origin:   t.origin,
+		lo:       t.lo,
+		hi:       t.hi,
+		sparse:   maps.Clone(t.sparse),
+		blank:    t.blank,
+		blankSet: t.blankSet,
+	}
+}
+
+// withBlank returns a copy of the tape using the given blank symbol,
+// cells holding the blank symbol of t become blank cells of the copy.
+func (t *Tape) withBlank(blank rune) *Tape {
+	if t.Blank() == blank {
+		clone := t.Clone()
+		clone.blank = blank
+		clone.blankSet = true
+
+		return clone
+	}
+
+	clone := NewTape(blank)
+
+	t.each(func(pos int, sym rune) {
+		if sym != t.Blank() {
+			clone.Write(pos, sym)
+		}
+	})
+
+	return clone
+}
@@ -0,0 +1,212 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTape_ReadWrite(t *testing.T) {
+	t.Parallel()
+
+	tape := &turing.Tape{}
+	assert.Equal(t, 0, tape.Len())
+	assert.Equal(t, ' ', tape.Read(0))
+
+	tape.Write(3, 'a')
+	assert.Equal(t, 1, tape.Len())
+	assert.Equal(t, 'a', tape.Read(3))
+	assert.Equal(t, ' ', tape.Read(2))
+
+	// grow to the left and to the right far beyond the initial buffer
+	for i := -100; i <= 100; i++ {
+		if i%2 == 0 {
+			tape.Write(i, '1')
+		}
+	}
+
+	// only the written cells are counted
+	assert.Equal(t, 102, tape.Len())
+	assert.Equal(t, '1', tape.Read(-100))
+	assert.Equal(t, ' ', tape.Read(-99))
+	assert.Equal(t, 'a', tape.Read(3))
+	assert.Equal(t, '1', tape.Read(100))
+	assert.Equal(t, ' ', tape.Read(101))
+	assert.Equal(t, ' ', tape.Read(-1000))
+}
+
+func TestTape_Map(t *testing.T) {
+	t.Parallel()
+
+	cells := map[int]rune{-2: '1', -1: '1', 0: '+', 1: '1'}
+
+	tape := turing.TapeFromMap(cells)
+	assert.Equal(t, 4, tape.Len())
+	assert.Equal(t, cells, tape.Map())
+
+	// blank cells between written ones are not reported, written blanks are
+	tape.Write(3, '1')
+	tape.Write(5, ' ')
+	assert.Equal(t, 6, tape.Len())
+	assert.Equal(t, map[int]rune{-2: '1', -1: '1', 0: '+', 1: '1', 3: '1', 5: ' '}, tape.Map())
+}
+
+func TestTape_Clone(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromMap(map[int]rune{0: '1'})
+
+	clone := tape.Clone()
+	assert.Equal(t, tape, clone)
+
+	clone.Write(1, '1')
+	assert.Equal(t, ' ', tape.Read(1))
+	assert.Equal(t, '1', clone.Read(1))
+}
+
+// benchmarkTapeCells is the width of the sweep the tape benchmarks make,
+// it is similar to what programs in the tests cover.
+const benchmarkTapeCells = 1000
+
+func BenchmarkTape_Map(b *testing.B) {
+	tape := make(map[int]rune)
+
+	for i := 0; i < b.N; i++ {
+		// sweep the carriage right and back like unary programs do
+		for pos := 0; pos < benchmarkTapeCells; pos++ {
+			sym, ok := tape[pos]
+			if !ok {
+				sym = ' '
+			}
+
+			tape[pos] = sym
+		}
+
+		for pos := benchmarkTapeCells - 1; pos >= 0; pos-- {
+			sym, ok := tape[pos]
+			if !ok {
+				sym = ' '
+			}
+
+			tape[pos] = sym
+		}
+	}
+}
+
+func BenchmarkTape_Dense(b *testing.B) {
+	tape := &turing.Tape{}
+
+	for i := 0; i < b.N; i++ {
+		// sweep the carriage right and back like unary programs do
+		for pos := 0; pos < benchmarkTapeCells; pos++ {
+			tape.Write(pos, tape.Read(pos))
+		}
+
+		for pos := benchmarkTapeCells - 1; pos >= 0; pos-- {
+			tape.Write(pos, tape.Read(pos))
+		}
+	}
+}
+
+func TestTapeFromString(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromString(" 11+1 1 ", -3)
+	assert.Equal(t, map[int]rune{-2: '1', -1: '1', 0: '+', 1: '1', 3: '1'}, tape.Map())
+
+	s, pos := tape.Trimmed()
+	assert.Equal(t, "11+1 1", s)
+	assert.Equal(t, -2, pos)
+	assert.Equal(t, "11+1 1", tape.String())
+
+	s, pos = turing.TapeFromString("   ", 5).Trimmed()
+	assert.Empty(t, s)
+	assert.Equal(t, 0, pos)
+}
+
+func TestTape_Trimmed_WrittenBlanks(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromMap(map[int]rune{-1: ' ', 0: '1', 1: ' ', 2: '1', 3: ' '})
+
+	s, pos := tape.Trimmed()
+	assert.Equal(t, "1 1", s)
+	assert.Equal(t, 0, pos)
+}
+
+func TestTape_Sparse(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromMap(map[int]rune{0: '1', 1: ' '})
+	tape.Write(1<<40, '1')
+	tape.Write(-1<<40, ' ')
+
+	assert.Equal(t, 4, tape.Len())
+	assert.Equal(t, map[int]rune{0: '1', 1: ' ', 1 << 40: '1', -1 << 40: ' '}, tape.Map())
+	assert.Equal(t, '1', tape.Read(1<<40))
+	assert.Equal(t, ' ', tape.Read(1<<39))
+	assert.Equal(t, " 1 ", tape.Window(1<<40, 1))
+
+	// writing more cells keeps the tape sparse
+	tape.Write(2, '1')
+	assert.Equal(t, 5, tape.Len())
+
+	clone := tape.Clone()
+	clone.Write(2, 'x')
+	assert.Equal(t, '1', tape.Read(2))
+
+	tape.Write(1<<40, ' ')
+
+	s, pos := tape.Trimmed()
+	assert.Equal(t, "1 1", s)
+	assert.Equal(t, 0, pos)
+}
+
+func TestTape_Window(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromString("abc", 0)
+	assert.Equal(t, "  abc", tape.Window(0, 2))
+	assert.Equal(t, "bc ", tape.Window(2, 1))
+	assert.Equal(t, "b", tape.Window(1, 0))
+}
+
+func TestTape_Equal(t *testing.T) {
+	t.Parallel()
+
+	tape := turing.TapeFromString("11+111", -2)
+
+	assert.True(t, tape.Equal(turing.TapeFromMap(map[int]rune{
+		-3: ' ', -2: '1', -1: '1', 0: '+', 1: '1', 2: '1', 3: '1', 4: ' ',
+	})))
+	assert.True(t, tape.Equal(turing.TapeFromString("  11+111   ", -4)))
+	assert.False(t, tape.Equal(turing.TapeFromString("11+111", -1)))
+	assert.False(t, tape.Equal(turing.TapeFromString("11+11", -2)))
+	assert.True(t, (&turing.Tape{}).Equal(turing.TapeFromString("  ", 0)))
+}
+
+func TestNewTape_Blank(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, turing.DefaultBlank, (&turing.Tape{}).Blank())
+
+	tape := turing.NewTape('_')
+	assert.Equal(t, '_', tape.Blank())
+	assert.Equal(t, '_', tape.Read(10))
+
+	tape.WriteString(-1, "_1_1_")
+	assert.Equal(t, 2, tape.Len())
+	assert.Equal(t, "___1_1___", tape.Window(1, 4))
+
+	s, pos := tape.Trimmed()
+	assert.Equal(t, "1_1", s)
+	assert.Equal(t, 0, pos)
+
+	assert.Equal(t, '_', tape.Clone().Blank())
+
+	// zero is a blank symbol like any other
+	tape = turing.NewTape(0)
+	assert.Equal(t, rune(0), tape.Blank())
+	assert.Equal(t, rune(0), tape.Read(10))
+}
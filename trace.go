@@ -0,0 +1,106 @@
+package turing
+
+import "fmt"
+
+// StepRecord describes a single transition applied by the machine.
+type StepRecord struct {
+	// Step is the number of the step, starting from 1.
+	Step uint
+
+	// State is the state the machine was in before the step.
+	State string
+
+	// Symbol is the symbol read under the carriage.
+	Symbol rune
+
+	// Transition is the transition applied for State and Symbol.
+	Transition Transition
+
+	// CarriageBefore and CarriageAfter are the carriage positions around the move.
+	CarriageBefore int
+	CarriageAfter  int
+}
+
+// Tracer receives a record for every step made by the machine.
+type Tracer interface {
+	Trace(record StepRecord)
+}
+
+// traceSource is implemented by tracers able to return what they have recorded,
+// the machine attaches these records to execution errors.
+type traceSource interface {
+	Records() []StepRecord
+}
+
+// traceResetter is implemented by tracers keeping the records of a single run,
+// the machine resets them when it is reset.
+type traceResetter interface {
+	Reset()
+}
+
+// Recorder is a Tracer that keeps either the full trace or a ring buffer
+// with the last records only.
+type Recorder struct {
+	records []StepRecord
+
+	// maximum number of kept records, 0 means unlimited
+	limit int
+
+	// position of the oldest record once the ring buffer is full
+	next int
+}
+
+// NewRecorder creates a Recorder keeping the last limit records.
+// To keep the full trace pass 0.
+func NewRecorder(limit int) *Recorder {
+	return &Recorder{limit: limit}
+}
+
+// Trace implements Tracer.
+func (r *Recorder) Trace(record StepRecord) {
+	if r.limit <= 0 || len(r.records) < r.limit {
+		r.records = append(r.records, record)
+		return
+	}
+
+	r.records[r.next] = record
+	r.next = (r.next + 1) % r.limit
+}
+
+// Records returns a copy of the recorded steps, oldest first.
+func (r *Recorder) Records() []StepRecord {
+	records := make([]StepRecord, 0, len(r.records))
+	records = append(records, r.records[r.next:]...)
+	records = append(records, r.records[:r.next]...)
+
+	return records
+}
+
+// Reset drops all recorded steps.
+func (r *Recorder) Reset() {
+	r.records = nil
+	r.next = 0
+}
+
+// clone returns an independent copy of the recorder.
+func (r *Recorder) clone() *Recorder {
+	c := *r
+	c.records = append([]StepRecord(nil), r.records...)
+
+	return &c
+}
+
+// TraceError is returned by the machine on failure when its tracer keeps records,
+// it carries the tail of the trace that led to the error.
+type TraceError struct {
+	Err   error
+	Trace []StepRecord
+}
+
+func (e *TraceError) Error() string {
+	return fmt.Sprintf("%v (traced steps: %d)", e.Err, len(e.Trace))
+}
+
+func (e *TraceError) Unwrap() error {
+	return e.Err
+}
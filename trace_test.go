@@ -0,0 +1,189 @@
+package turing_test
+
+import (
+	"testing"
+
+	"github.com/asphodex/go-turing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Full(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+		},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	recorder := turing.NewRecorder(0)
+	machine.SetTracer(recorder)
+
+	_, err = machine.Exec(0, map[int]rune{0: '1', 1: '1'})
+	require.NoError(t, err)
+
+	assert.Equal(t, []turing.StepRecord{
+		{
+			Step:           1,
+			State:          "Q1",
+			Symbol:         '1',
+			Transition:     turing.Transition{NextState: "Q1", Move: turing.Right, Write: '1'},
+			CarriageBefore: 0,
+			CarriageAfter:  1,
+		},
+		{
+			Step:           2,
+			State:          "Q1",
+			Symbol:         '1',
+			Transition:     turing.Transition{NextState: "Q1", Move: turing.Right, Write: '1'},
+			CarriageBefore: 1,
+			CarriageAfter:  2,
+		},
+		{
+			Step:           3,
+			State:          "Q1",
+			Symbol:         ' ',
+			Transition:     turing.Transition{NextState: "Q0", Move: turing.Stay, Write: '1'},
+			CarriageBefore: 2,
+			CarriageAfter:  2,
+		},
+	}, recorder.Records())
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Records())
+}
+
+func TestRecorder_RingBuffer(t *testing.T) {
+	t.Parallel()
+
+	recorder := turing.NewRecorder(3)
+
+	for i := uint(1); i <= 7; i++ {
+		recorder.Trace(turing.StepRecord{Step: i})
+	}
+
+	records := recorder.Records()
+	require.Len(t, records, 3)
+	assert.Equal(t, uint(5), records[0].Step)
+	assert.Equal(t, uint(6), records[1].Step)
+	assert.Equal(t, uint(7), records[2].Step)
+}
+
+func TestMachine_Exec_TraceError(t *testing.T) {
+	t.Parallel()
+
+	// Go right over ones and fail on the blank cell.
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	machine.SetTracer(turing.NewRecorder(2))
+
+	tape, err := machine.Exec(0, map[int]rune{0: '1', 1: '1', 2: '1'})
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+	assert.Nil(t, tape)
+
+	var traceErr *turing.TraceError
+	require.ErrorAs(t, err, &traceErr)
+	require.Len(t, traceErr.Trace, 2)
+	assert.Equal(t, uint(2), traceErr.Trace[0].Step)
+	assert.Equal(t, 3, traceErr.Trace[1].CarriageAfter)
+}
+
+func TestMachine_Exec_TraceError_Rerun(t *testing.T) {
+	t.Parallel()
+
+	// Go right over ones and fail on the blank cell.
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	recorder := turing.NewRecorder(0)
+	machine.SetTracer(recorder)
+
+	_, err = machine.Exec(0, map[int]rune{0: '1', 1: '1', 2: '1'})
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+	require.Len(t, recorder.Records(), 3)
+
+	// the records of the previous run are dropped
+	_, err = machine.Exec(0, map[int]rune{0: '1'})
+
+	var traceErr *turing.TraceError
+	require.ErrorAs(t, err, &traceErr)
+	require.Len(t, traceErr.Trace, 1)
+	assert.Equal(t, uint(1), traceErr.Trace[0].Step)
+
+	// the copy records its own steps
+	_, err = machine.Copy().Exec(0, map[int]rune{0: '1', 1: '1'})
+	require.ErrorAs(t, err, &traceErr)
+	require.Len(t, traceErr.Trace, 2)
+	require.Len(t, recorder.Records(), 1)
+}
+
+type countingTracer struct {
+	steps int
+}
+
+func (c *countingTracer) Trace(turing.StepRecord) {
+	c.steps++
+}
+
+func TestMachine_Exec_TracerWithoutRecords(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	tracer := &countingTracer{}
+	machine.SetTracer(tracer)
+
+	_, err = machine.Exec(0, map[int]rune{0: '1'})
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+	assert.Equal(t, 1, tracer.steps)
+
+	var traceErr *turing.TraceError
+	assert.NotErrorAs(t, err, &traceErr)
+}
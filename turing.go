@@ -14,34 +14,63 @@ const (
 	Left  Direction = -1
 	Right Direction = 1
 	Stay  Direction = 0
+
+	// Up and Down move the carriage of a GridMachine along the Y axis,
+	// one-dimensional machines reject them.
+	Up   Direction = 2
+	Down Direction = -2
 )
 
+// TapeMode selects whether the tape is infinite in both directions or bounded on the left.
+type TapeMode int
+
+// Available tape modes.
+const (
+	// TwoWay tape is infinite in both directions, it is the default.
+	TwoWay TapeMode = iota
+
+	// OneWayStay tape starts at cell 0, moving left from it leaves the carriage in place.
+	OneWayStay
+
+	// OneWayError tape starts at cell 0, moving left from it fails the execution with ErrLeftEdge.
+	OneWayError
+)
+
+// DefaultBlank is the blank symbol used unless another one is configured.
+const DefaultBlank = ' '
+
 // Program for Turing machine.
 type Program map[string]map[rune]Transition
 
-// Validate checks move, write and next state fields for program.
-func (tp Program) Validate(alphabet map[rune]struct{}, terminalState string) error {
-	for state, stateTransitions := range tp {
-		for symbol, transition := range stateTransitions {
-			if transition.Move != Left && transition.Move != Right && transition.Move != Stay {
-				return fmt.Errorf("%w: %d for state %q, symbol %q", ErrInvalidMoveDirection, transition.Move, state, symbol)
-			}
-
-			if _, ok := alphabet[transition.Write]; !ok {
-				return fmt.Errorf("%w: %q for state %q", ErrUnexpectedSymbol, transition.Write, state)
-			}
-
-			if transition.NextState == terminalState {
-				continue
-			}
-
-			if _, ok := tp[transition.NextState]; !ok {
-				return fmt.Errorf("%w: %q", ErrStateNotFound, transition.NextState)
-			}
+// Validate checks move, write and next state fields for program, and that every transition
+// is keyed on a symbol of the alphabet. Any of the halting states is a valid next state.
+// All the problems found are joined into the returned error, ordered by state and symbol.
+func (tp Program) Validate(alphabet map[rune]struct{}, haltingStates ...string) error {
+	c := newProgramCheck(alphabet, haltingStates, func(state string) bool {
+		_, ok := tp[state]
+
+		return ok
+	})
+
+	for _, state := range sortedKeys(tp) {
+		for _, symbol := range sortedKeys(tp[state]) {
+			transition := tp[state][symbol]
+			at := fmt.Sprintf("state %q, symbol %q", state, symbol)
+
+			c.read(symbol, state)
+			c.move(transition.Move, isMove(transition.Move), at)
+			c.write(transition.Write, at)
+			c.next(transition.NextState, at)
 		}
 	}
 
-	return nil
+	return c.err()
+}
+
+// ValidateStart is Validate also checking that the start state is defined in program
+// or is one of the halting states.
+func (tp Program) ValidateStart(alphabet map[rune]struct{}, start string, haltingStates ...string) error {
+	return validateStart(tp, start, haltingStates, tp.Validate(alphabet, haltingStates...))
 }
 
 type Machine struct {
@@ -51,23 +80,36 @@ type Machine struct {
 	//           ↓
 	// [ ][ ][ ][A][!][ ][ ]
 	// infinite tape with carriage
-	tape map[int]rune
+	tape *Tape
 
-	// current state (Q1 for example)
-	state string
+	// id of the current state in the compiled program (Q1 for example)
+	state int
 
 	// the startState from which the algorithm will start
 	startState string
 
-	// the terminalState that the algorithm will terminate
-	terminalState string
+	// the haltingStates that the algorithm will terminate in (Q0 for example)
+	haltingStates map[string]HaltKind
 
 	// "A!" for example
 	alphabet map[rune]struct{}
 
+	// symbol of the empty cells, always part of the alphabet
+	blank rune
+
 	// program[Q1][A] for example
 	program map[string]map[rune]Transition
 
+	// program translated for fast execution
+	compiled *compiledProgram
+
+	// id of startState in the compiled program
+	start int
+
+	// halting[id] reports whether the state is halting, kinds[id] classifies it
+	halting []bool
+	kinds   []HaltKind
+
 	// number of executed steps
 	steps uint
 
@@ -75,6 +117,27 @@ type Machine struct {
 	maxTapeLength uint
 
 	maxSteps uint
+
+	// receives a record for every step, may be nil
+	tracer Tracer
+
+	// behaviour on undefined transitions
+	undefined UndefinedTransition
+
+	// whether the tape is bounded on the left
+	tapeMode TapeMode
+
+	// the machine halted on an undefined transition
+	stuck bool
+
+	// infinite loop checks made in addition to the Stay self-loop one
+	detection LoopDetection
+
+	// repeating configurations detector, nil unless DetectCycles is set
+	cycles *cycleDetector
+
+	// translated cycles detector, nil unless DetectTranslatedCycles is set
+	translations *translationDetector
 }
 
 // A! - alphabet
@@ -95,7 +158,8 @@ var (
 	// ErrStartStateEmpty is returned when the start state parameter is empty.
 	ErrStartStateEmpty = errors.New("start state is empty")
 
-	// ErrTerminalStateEmpty is returned when the terminal state parameter is empty.
+	// ErrTerminalStateEmpty is returned when the terminal state parameter is empty
+	// or no halting states are given.
 	ErrTerminalStateEmpty = errors.New("terminal state is empty")
 
 	// ErrInvalidMaxTapeLength is returned when maxTapeLength is zero.
@@ -109,7 +173,7 @@ var (
 )
 
 // NewMachine creates a new Turing machine with the specified configuration.
-// Space character is automatically included in the alphabet.
+// Space character is automatically included in the alphabet as the blank symbol.
 // To avoid max steps constraint pass 0.
 func NewMachine(
 	alphabet, // "ABC" for example, space is already included
@@ -119,51 +183,29 @@ func NewMachine(
 	maxTapeLength,
 	maxSteps uint, // pass 0 to disable
 ) (*Machine, error) {
-	a := make(map[rune]struct{}, len(alphabet))
-	for _, sym := range alphabet {
-		a[sym] = struct{}{}
-	}
-
-	a[' '] = struct{}{}
-
-	if startState == "" {
-		return nil, ErrStartStateEmpty
-	}
-
-	if terminalState == "" {
-		return nil, ErrTerminalStateEmpty
-	}
-
-	if maxTapeLength == 0 {
-		return nil, ErrInvalidMaxTapeLength
-	}
-
-	if err := program.Validate(a, terminalState); err != nil {
-		return nil, err
-	}
-
-	return &Machine{
-		tape:          make(map[int]rune),
-		startState:    startState,
-		terminalState: terminalState,
-		alphabet:      a,
-		program:       program,
-		maxTapeLength: maxTapeLength,
-		maxSteps:      maxSteps,
-	}, nil
+	return New(
+		program,
+		WithAlphabet(alphabet),
+		WithStartState(startState),
+		WithTerminalState(terminalState),
+		WithMaxTapeLength(maxTapeLength),
+		WithMaxSteps(maxSteps),
+	)
 }
 
+// Copy returns an independent copy of the machine. A Recorder is copied with its records,
+// other tracers keeping records are not shared and the copy has no tracer then.
 func (m *Machine) Copy() *Machine {
-	newTape := make(map[int]rune, len(m.tape))
-	for k, v := range m.tape {
-		newTape[k] = v
-	}
-
 	newAlphabet := make(map[rune]struct{}, len(m.alphabet))
 	for k, v := range m.alphabet {
 		newAlphabet[k] = v
 	}
 
+	newHaltingStates := make(map[string]HaltKind, len(m.haltingStates))
+	for k, v := range m.haltingStates {
+		newHaltingStates[k] = v
+	}
+
 	newProgram := make(map[string]map[rune]Transition, len(m.program))
 	for state, transitions := range m.program {
 		newTransitions := make(map[rune]Transition, len(transitions))
@@ -173,20 +215,47 @@ func (m *Machine) Copy() *Machine {
 		newProgram[state] = newTransitions
 	}
 
+	tracer := m.tracer
+	if recorder, ok := tracer.(*Recorder); ok {
+		tracer = recorder.clone()
+	} else if _, ok := tracer.(traceSource); ok {
+		tracer = nil
+	}
+
 	return &Machine{
 		carriage:      m.carriage,
-		tape:          newTape,
+		tape:          m.tape.Clone(),
 		state:         m.state,
 		startState:    m.startState,
-		terminalState: m.terminalState,
+		haltingStates: newHaltingStates,
 		alphabet:      newAlphabet,
+		blank:         m.blank,
 		program:       newProgram,
+		compiled:      m.compiled,
+		start:         m.start,
+		halting:       m.halting,
+		kinds:         m.kinds,
 		steps:         m.steps,
 		maxTapeLength: m.maxTapeLength,
 		maxSteps:      m.maxSteps,
+		tracer:        tracer,
+		undefined:     m.undefined,
+		tapeMode:      m.tapeMode,
+		stuck:         m.stuck,
+		detection:     m.detection,
+		cycles:        m.cycles.clone(),
+		translations:  m.translations.clone(),
 	}
 }
 
+// SetTracer sets the tracer receiving a record for every step, pass nil to disable tracing.
+// If the tracer keeps records, like Recorder does, errors returned by Step and ExecCtx
+// are wrapped in TraceError carrying the recorded steps. A tracer having a Reset method,
+// like Recorder, is reset with the machine, so its records only cover the current run.
+func (m *Machine) SetTracer(t Tracer) {
+	m.tracer = t
+}
+
 // Exec executes the Turing machine program with the starting carriage position
 // and input tape, returning the final tape state upon completion or an error
 // if execution fails.
@@ -201,34 +270,118 @@ func (m *Machine) Exec(carriage int, input map[int]rune) (map[int]rune, error) {
 // Returns the final tape state upon successful completion, or an error if the execution fails
 // or the context is cancelled.
 func (m *Machine) ExecCtx(ctx context.Context, carriage int, input map[int]rune) (map[int]rune, error) {
+	tape, err := m.ExecTapeCtx(ctx, carriage, m.tapeFromMap(input))
+	if err != nil {
+		return nil, err
+	}
+
+	return tape.Map(), nil
+}
+
+// ExecTape is Exec working with Tape instead of map.
+func (m *Machine) ExecTape(carriage int, input *Tape) (*Tape, error) {
+	return m.ExecTapeCtx(context.Background(), carriage, input)
+}
+
+// ExecTapeCtx is ExecCtx working with Tape instead of map.
+// The input tape is copied and is not modified by the machine,
+// its blank cells become blank cells of the machine.
+func (m *Machine) ExecTapeCtx(ctx context.Context, carriage int, input *Tape) (*Tape, error) {
+	result, err := m.RunCtx(ctx, carriage, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Tape, nil
+}
+
+// Snapshot is a point-in-time view of the machine, taken between steps.
+type Snapshot struct {
+	// State is the state the machine is currently in.
+	State string
+
+	// Carriage is the position of the carriage on the tape.
+	Carriage int
+
+	// Steps is the number of transitions applied since the last Reset.
+	Steps uint
+
+	// Halted reports whether a halting state has been reached.
+	Halted bool
+
+	// Tape is a copy of the tape, changing it does not affect the machine.
+	Tape map[int]rune
+}
+
+// Reset loads the input tape, places the carriage and puts the machine into
+// the start state, so the program can be driven with Step.
+// The input map is copied and is not modified by the machine.
+func (m *Machine) Reset(carriage int, input map[int]rune) {
+	m.ResetTape(carriage, m.tapeFromMap(input))
+}
+
+// ResetTape is Reset working with Tape instead of map.
+// The input tape is copied and is not modified by the machine,
+// its blank cells become blank cells of the machine.
+func (m *Machine) ResetTape(carriage int, input *Tape) {
 	m.carriage = carriage
-	m.state = m.startState
+	m.state = m.start
 	m.steps = 0
+	m.stuck = false
 
-	tape := make(map[int]rune, len(input))
-	for i, symbol := range input {
-		tape[i] = symbol
+	m.tape = input.withBlank(m.blank)
+
+	if resetter, ok := m.tracer.(traceResetter); ok {
+		resetter.Reset()
 	}
 
-	m.tape = tape
+	m.cycles = nil
+	if m.detection&DetectCycles != 0 {
+		m.cycles = newCycleDetector(m)
+	}
 
-	var (
-		ok  = true
-		err error
-	)
+	m.translations = nil
+	if m.detection&DetectTranslatedCycles != 0 {
+		m.translations = newTranslationDetector()
+	}
+}
 
-	for ok {
-		if ctx.Err() != nil {
-			return nil, ctx.Err() //nolint:wrapcheck
-		}
+// tapeFromMap creates a tape with the machine blank symbol holding the given cells.
+func (m *Machine) tapeFromMap(cells map[int]rune) *Tape {
+	tape := NewTape(m.blank)
+	tape.WriteMap(cells)
 
-		ok, err = m.step()
-		if err != nil {
-			return nil, err
-		}
+	return tape
+}
+
+// Step applies a single transition with exactly the same checks as ExecCtx.
+// It reports whether the machine is still running after the step: false is
+// returned once a halting state is reached or when an error occurs.
+// Reset must be called before the first Step.
+func (m *Machine) Step() (bool, error) {
+	ok, err := m.step()
+	if err != nil {
+		return false, m.traceError(err)
 	}
 
-	return m.tape, nil
+	return ok && !m.Halted(), nil
+}
+
+// Halted reports whether the machine has reached a halting state
+// or halted on an undefined transition.
+func (m *Machine) Halted() bool {
+	return m.halting[m.state] || m.stuck
+}
+
+// Snapshot returns the current state, carriage position, step count and a copy of the tape.
+func (m *Machine) Snapshot() Snapshot {
+	return Snapshot{
+		State:    m.compiled.states[m.state],
+		Carriage: m.carriage,
+		Steps:    m.steps,
+		Halted:   m.Halted(),
+		Tape:     m.tape.Map(),
+	}
 }
 
 var (
@@ -244,61 +397,179 @@ var (
 	// ErrUnexpectedSymbol is returned when the machine reads a symbol not in its alphabet.
 	ErrUnexpectedSymbol = errors.New("unexpected symbol")
 
+	// ErrLeftEdge is returned when the carriage moves left from cell 0 of a OneWayError tape
+	// or starts at a negative position of a one-way tape.
+	ErrLeftEdge = errors.New("left edge of the tape")
+
 	// ErrTapeOver is returned when the tape exceeds its maximum allowed length.
 	ErrTapeOver = errors.New("tape is over")
 )
 
+// tapeExcerptRadius is the number of cells on each side of the carriage kept in ExecError.
+const tapeExcerptRadius = 10
+
+// ExecError is returned when the execution fails. It wraps one of the sentinel errors
+// above and describes the configuration the machine stopped in.
+type ExecError struct {
+	Err error
+
+	// State is the current state of the machine.
+	State string
+
+	// Symbol is the symbol under the carriage.
+	Symbol rune
+
+	// Carriage is the carriage position.
+	Carriage int
+
+	// Steps is the number of steps made before the failure.
+	Steps uint
+
+	// Tape is an excerpt of the tape around the carriage,
+	// TapeStart is the position of its first cell.
+	Tape      string
+	TapeStart int
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("%v: state %q, symbol %q, carriage %d, step %d", e.Err, e.State, e.Symbol, e.Carriage, e.Steps)
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// execError wraps err into ExecError describing the current configuration.
+func (m *Machine) execError(err error) *ExecError {
+	return &ExecError{
+		Err:       err,
+		State:     m.compiled.states[m.state],
+		Symbol:    m.read(),
+		Carriage:  m.carriage,
+		Steps:     m.steps,
+		Tape:      m.tape.Window(m.carriage, tapeExcerptRadius),
+		TapeStart: m.carriage - tapeExcerptRadius,
+	}
+}
+
 func (m *Machine) step() (bool, error) {
-	if m.state == m.terminalState {
+	if m.Halted() {
 		return false, nil
 	}
 
+	if m.tapeMode != TwoWay && m.carriage < 0 {
+		return false, m.execError(ErrLeftEdge)
+	}
+
 	sym := m.read()
 
-	if _, ok := m.alphabet[sym]; !ok {
-		return false, fmt.Errorf("%w: %q", ErrUnexpectedSymbol, sym)
+	symbol, ok := m.compiled.symbol(sym)
+	if !ok {
+		return false, m.execError(ErrUnexpectedSymbol)
 	}
 
-	transition, ok := m.program[m.state][sym]
-	if !ok {
-		return false, fmt.Errorf("%w: state %q, symbol %q", ErrTransitionNotFound, m.state, sym)
+	state := m.state
+
+	transition := m.compiled.transition(m.state, symbol)
+	if !transition.defined {
+		if m.undefined == UndefinedError {
+			return false, m.execError(ErrTransitionNotFound)
+		}
+
+		m.stuck = true
+
+		return false, nil
+	}
+
+	move, err := m.bound(transition.Move)
+	if err != nil {
+		return false, err
 	}
 
 	// is current transition an infinite loop?
-	if transition.Move == Stay && transition.NextState == m.state && transition.Write == sym {
-		return false, fmt.Errorf("%w: state %q, symbol %q", ErrInfiniteLoop, m.state, sym)
+	if move == Stay && transition.next == m.state && transition.Write == sym {
+		return false, m.execError(ErrInfiniteLoop)
 	}
 
+	carriage := m.carriage
+
 	m.write(transition.Write)
-	m.move(transition.Move)
-	m.state = transition.NextState
+
+	if m.cycles != nil {
+		m.cycles.write(carriage, sym, transition.Write)
+	}
+
+	m.move(move)
+	m.state = transition.next
 	m.steps++
 
-	if uint(len(m.tape)) >= m.maxTapeLength {
-		return false, fmt.Errorf("%w, carriage: %d", ErrTapeOver, m.carriage)
+	if m.tracer != nil {
+		m.tracer.Trace(StepRecord{
+			Step:           m.steps,
+			State:          m.compiled.states[state],
+			Symbol:         sym,
+			Transition:     transition.Transition,
+			CarriageBefore: carriage,
+			CarriageAfter:  m.carriage,
+		})
+	}
+
+	if m.cycles != nil {
+		if cycle := m.cycles.check(m); cycle != nil {
+			return false, m.execError(cycle)
+		}
+	}
+
+	if m.translations != nil {
+		if cycle := m.translations.check(m); cycle != nil {
+			return false, m.execError(cycle)
+		}
+	}
+
+	if uint(m.tape.Len()) >= m.maxTapeLength {
+		return false, m.execError(ErrTapeOver)
 	}
 
 	if m.maxSteps > 0 && m.steps >= m.maxSteps {
-		return false, ErrStepsExceeded
+		return false, m.execError(ErrStepsExceeded)
 	}
 
 	return true, nil
 }
 
-// The read method allows reading a symbol from the cell the carriage points to.
-// If there is no symbol at this position, it returns ' '.
-func (m *Machine) read() rune {
-	if sym, ok := m.tape[m.carriage]; ok {
-		return sym
+// traceError attaches the recorded steps to err if the tracer keeps them.
+func (m *Machine) traceError(err error) error {
+	source, ok := m.tracer.(traceSource)
+	if !ok {
+		return err
 	}
 
-	return ' '
+	return &TraceError{Err: err, Trace: source.Records()}
+}
+
+// The read method allows reading a symbol from the cell the carriage points to.
+// If there is no symbol at this position, it returns the blank symbol.
+func (m *Machine) read() rune {
+	return m.tape.Read(m.carriage)
 }
 
 func (m *Machine) write(sym rune) {
-	m.tape[m.carriage] = sym
+	m.tape.Write(m.carriage, sym)
 }
 
 func (m *Machine) move(d Direction) {
 	m.carriage += int(d)
 }
+
+// bound returns the move the carriage actually makes on the tape of the machine mode.
+func (m *Machine) bound(d Direction) (Direction, error) {
+	if m.tapeMode == TwoWay || m.carriage+int(d) >= 0 {
+		return d, nil
+	}
+
+	if m.tapeMode == OneWayStay {
+		return Stay, nil
+	}
+
+	return d, m.execError(ErrLeftEdge)
+}
@@ -137,6 +137,56 @@ func TestProgram_Validate(t *testing.T) {
 	}
 }
 
+func TestProgram_Validate_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.Program{
+		"Q2": {
+			'1': {NextState: "Q9", Move: turing.Stay, Write: '1'},
+		},
+		"Q1": {
+			'x': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			' ': {NextState: "Q0", Move: 3, Write: 'y'},
+		},
+	}
+
+	err := program.Validate(alphabet, "Q0")
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+	require.ErrorIs(t, err, turing.ErrInvalidMoveDirection)
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+
+	expected := strings.Join([]string{
+		`invalid move direction: 3 for state "Q1", symbol ' '`,
+		`unexpected symbol: 'y' for state "Q1", symbol ' '`,
+		`unexpected symbol: 'x' read by state "Q1"`,
+		`state not found: "Q9" for state "Q2", symbol '1'`,
+	}, "\n")
+
+	// the order does not depend on the map iteration order
+	for range 10 {
+		require.EqualError(t, program.Validate(alphabet, "Q0"), expected)
+	}
+}
+
+func TestProgram_ValidateStart(t *testing.T) {
+	t.Parallel()
+
+	alphabet := map[rune]struct{}{'1': {}, ' ': {}}
+
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q0", Move: turing.Stay, Write: '1'}},
+	}
+
+	require.NoError(t, program.ValidateStart(alphabet, "Q1", "Q0"))
+	require.NoError(t, program.ValidateStart(alphabet, "Q0", "Q0"))
+	require.ErrorIs(t, program.ValidateStart(alphabet, "Q2", "Q0"), turing.ErrStateNotFound)
+
+	_, err := turing.NewMachine("1", "Q2", "Q0", program, 20, 20)
+	require.ErrorIs(t, err, turing.ErrStateNotFound)
+}
+
 func TestMachine_Exec_Plus_One_Program(t *testing.T) {
 	t.Parallel()
 
@@ -411,6 +461,35 @@ func TestMachine_Exec_TapeOver(t *testing.T) {
 	assert.Nil(t, tape)
 }
 
+func TestMachine_Exec_FarApartInput(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q0", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		100,
+		0,
+	)
+	require.NoError(t, err)
+
+	// the cells and the carriage lie too far apart to be held contiguously
+	input := map[int]rune{0: '1', 1 << 40: '1'}
+
+	tape, err := machine.Exec(0, input)
+	require.NoError(t, err)
+	assert.Equal(t, input, tape)
+
+	tape, err = machine.Exec(-1<<40, map[int]rune{-1 << 40: '1'})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]rune{-1 << 40: '1'}, tape)
+}
+
 func TestMachine_Exec_InfiniteLoop(t *testing.T) {
 	t.Parallel()
 
@@ -481,3 +560,293 @@ func TestMachine_Copy(t *testing.T) {
 	newMachine := machine.Copy()
 	require.Equal(t, machine, newMachine)
 }
+
+func TestMachine_Step(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '1'},
+			'1': {NextState: "Q1", Move: turing.Left, Write: '1'},
+		},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	machine.Reset(1, map[int]rune{0: '1', 1: '1'})
+
+	snapshot := machine.Snapshot()
+	assert.Equal(t, "Q1", snapshot.State)
+	assert.Equal(t, 1, snapshot.Carriage)
+	assert.Equal(t, uint(0), snapshot.Steps)
+	assert.False(t, snapshot.Halted)
+
+	expected := []struct {
+		state    string
+		carriage int
+	}{
+		{state: "Q1", carriage: 0},
+		{state: "Q1", carriage: -1},
+		{state: "Q0", carriage: -1},
+	}
+
+	for i, e := range expected {
+		running, err := machine.Step()
+		require.NoError(t, err)
+		assert.Equal(t, i < len(expected)-1, running)
+
+		snapshot = machine.Snapshot()
+		assert.Equal(t, e.state, snapshot.State)
+		assert.Equal(t, e.carriage, snapshot.Carriage)
+		assert.Equal(t, uint(i+1), snapshot.Steps)
+	}
+
+	assert.True(t, machine.Halted())
+	assert.Equal(t, map[int]rune{-1: '1', 0: '1', 1: '1'}, snapshot.Tape)
+
+	// snapshot tape is a copy
+	snapshot.Tape[5] = '1'
+	assert.NotContains(t, machine.Snapshot().Tape, 5)
+
+	running, err := machine.Step()
+	require.NoError(t, err)
+	assert.False(t, running)
+	assert.Equal(t, uint(3), machine.Snapshot().Steps)
+}
+
+func TestMachine_Step_Error(t *testing.T) {
+	t.Parallel()
+
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		20,
+		10,
+	)
+	require.NoError(t, err)
+
+	machine.Reset(0, map[int]rune{0: '1'})
+
+	running, err := machine.Step()
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	running, err = machine.Step()
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+	assert.False(t, running)
+	assert.False(t, machine.Halted())
+}
+
+func TestMachine_Exec_ExecError(t *testing.T) {
+	t.Parallel()
+
+	// Go right over ones and fail on the blank cell.
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q1", Move: turing.Right, Write: '1'}},
+	}
+
+	machine, err := turing.NewMachine(
+		"1",
+		"Q1",
+		"Q0",
+		program,
+		100,
+		100,
+	)
+	require.NoError(t, err)
+
+	_, err = machine.Exec(0, map[int]rune{0: '1', 1: '1', 2: '1'})
+	require.ErrorIs(t, err, turing.ErrTransitionNotFound)
+
+	var execErr *turing.ExecError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "Q1", execErr.State)
+	assert.Equal(t, ' ', execErr.Symbol)
+	assert.Equal(t, 3, execErr.Carriage)
+	assert.Equal(t, uint(3), execErr.Steps)
+	assert.Equal(t, -7, execErr.TapeStart)
+	assert.Equal(t, "       111           ", execErr.Tape)
+}
+
+func TestMachine_Exec_ExecError_StepsExceeded(t *testing.T) {
+	t.Parallel()
+
+	// Go left infinitely.
+	program := turing.Program{
+		"Q1": {' ': {NextState: "Q1", Move: turing.Left, Write: ' '}},
+	}
+
+	machine, err := turing.NewMachine(
+		"",
+		"Q1",
+		"Q0",
+		program,
+		10000,
+		10,
+	)
+	require.NoError(t, err)
+
+	_, err = machine.Exec(0, map[int]rune{})
+
+	var execErr *turing.ExecError
+	require.ErrorAs(t, err, &execErr)
+	require.ErrorIs(t, execErr, turing.ErrStepsExceeded)
+	assert.Equal(t, -10, execErr.Carriage)
+	assert.Equal(t, uint(10), execErr.Steps)
+}
+
+func TestMachine_ExecTape(t *testing.T) {
+	t.Parallel()
+
+	// This program for a Turing machine,
+	// calculates the function f(x)=x+y in the unary number system.
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q2", Move: turing.Right, Write: ' '},
+		},
+		"Q2": {
+			' ': {NextState: "Q3", Move: turing.Left, Write: ' '},
+			'1': {NextState: "Q2", Move: turing.Right, Write: '1'},
+			'+': {NextState: "Q2", Move: turing.Right, Write: '1'},
+		},
+		"Q3": {
+			'1': {NextState: "Q0", Move: turing.Stay, Write: ' '},
+		},
+	}
+
+	machine, err := turing.NewMachine(
+		"1+",
+		"Q1",
+		"Q0",
+		program,
+		100,
+		100,
+	)
+	require.NoError(t, err)
+
+	input := turing.TapeFromString("11+111", -2)
+
+	tape, err := machine.ExecTape(-2, input)
+	require.NoError(t, err)
+	assert.True(t, turing.TapeFromString("1111", -1).Equal(tape))
+
+	// input is not modified
+	assert.Equal(t, "11+111", input.String())
+}
+
+func TestMachine_ExecTape_Blank(t *testing.T) {
+	t.Parallel()
+
+	// Append one to the unary number and return to its beginning.
+	program := turing.Program{
+		"Q1": {
+			'1': {NextState: "Q1", Move: turing.Right, Write: '1'},
+			'_': {NextState: "Q2", Move: turing.Left, Write: '1'},
+		},
+		"Q2": {
+			'1': {NextState: "Q2", Move: turing.Left, Write: '1'},
+			'_': {NextState: "Q0", Move: turing.Right, Write: '_'},
+		},
+	}
+
+	machine, err := turing.New(
+		program,
+		turing.WithAlphabet("1"),
+		turing.WithBlank('_'),
+		turing.WithStartState("Q1"),
+		turing.WithTerminalState("Q0"),
+		turing.WithMaxTapeLength(20),
+		turing.WithMaxSteps(20),
+	)
+	require.NoError(t, err)
+
+	input := turing.NewTape('_')
+	input.WriteString(0, "11")
+
+	tape, err := machine.ExecTape(0, input)
+	require.NoError(t, err)
+	assert.Equal(t, '_', tape.Blank())
+
+	s, pos := tape.Trimmed()
+	assert.Equal(t, "111", s)
+	assert.Equal(t, 0, pos)
+
+	// blank cells of the input become blank cells of the machine
+	tape, err = machine.ExecTape(0, turing.TapeFromString("11", 0))
+	require.NoError(t, err)
+	assert.Equal(t, "111", tape.String())
+
+	result, err := machine.Exec(0, map[int]rune{0: '1'})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]rune{-1: '_', 0: '1', 1: '1'}, result)
+
+	// space is not a part of the alphabet anymore
+	_, err = machine.Exec(0, map[int]rune{0: '1', 1: ' '})
+	require.ErrorIs(t, err, turing.ErrUnexpectedSymbol)
+}
+
+func TestMachine_Run_TapeMode(t *testing.T) {
+	t.Parallel()
+
+	// marks the cell and steps left, then marks the cell it ends up on
+	program := turing.Program{
+		"Q1": {'1': {NextState: "Q2", Move: turing.Left, Write: 'a'}},
+		"Q2": {
+			'a': {NextState: "Q0", Move: turing.Stay, Write: 'b'},
+			' ': {NextState: "Q0", Move: turing.Stay, Write: '+'},
+		},
+	}
+
+	tt := []struct {
+		name     string
+		mode     turing.TapeMode
+		carriage int
+		want     string
+		err      error
+	}{
+		{name: "two-way", mode: turing.TwoWay, want: "+a"},
+		{name: "one-way stay", mode: turing.OneWayStay, want: "b"},
+		{name: "one-way error", mode: turing.OneWayError, err: turing.ErrLeftEdge},
+		{name: "negative start", mode: turing.OneWayStay, carriage: -1, err: turing.ErrLeftEdge},
+		{name: "negative start two-way", mode: turing.TwoWay, carriage: -1, want: "+a"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			machine, err := turing.New(
+				program,
+				turing.WithAlphabet("1ab+"),
+				turing.WithStartState("Q1"),
+				turing.WithTerminalState("Q0"),
+				turing.WithMaxTapeLength(10),
+				turing.WithTapeMode(tc.mode),
+			)
+			require.NoError(t, err)
+
+			result, err := machine.Run(tc.carriage, turing.TapeFromString("1", tc.carriage))
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, result.Tape.String())
+		})
+	}
+}
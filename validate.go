@@ -0,0 +1,107 @@
+package turing
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// programCheck collects the problems of a program, it is shared by the Validate methods
+// of all the kinds of programs. Transitions are checked in the order of states and keys
+// given by sortedKeys, so the joined error does not depend on the map order.
+type programCheck struct {
+	alphabet map[rune]struct{}
+	halting  map[string]struct{}
+
+	// defined reports whether the program has transitions for the state
+	defined func(state string) bool
+
+	errs []error
+}
+
+func newProgramCheck(alphabet map[rune]struct{}, haltingStates []string, defined func(string) bool) *programCheck {
+	halting := make(map[string]struct{}, len(haltingStates))
+	for _, state := range haltingStates {
+		halting[state] = struct{}{}
+	}
+
+	return &programCheck{
+		alphabet: alphabet,
+		halting:  halting,
+		defined:  defined,
+	}
+}
+
+// add records the problem if err is not nil.
+func (c *programCheck) add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// read checks that the symbol read by the state is in the alphabet.
+func (c *programCheck) read(sym rune, state string) {
+	if _, ok := c.alphabet[sym]; !ok {
+		c.add(fmt.Errorf("%w: %q read by state %q", ErrUnexpectedSymbol, sym, state))
+	}
+}
+
+// move checks the move of the transition at the given location,
+// valid reports whether the machine accepts it.
+func (c *programCheck) move(move Direction, valid bool, at string) {
+	if !valid {
+		c.add(fmt.Errorf("%w: %d for %s", ErrInvalidMoveDirection, move, at))
+	}
+}
+
+// write checks that the symbol written by the transition at the given location is in the alphabet.
+func (c *programCheck) write(sym rune, at string) {
+	if _, ok := c.alphabet[sym]; !ok {
+		c.add(fmt.Errorf("%w: %q for %s", ErrUnexpectedSymbol, sym, at))
+	}
+}
+
+// next checks that the next state of the transition at the given location
+// is defined in the program or is one of the halting states.
+func (c *programCheck) next(state, at string) {
+	if _, ok := c.halting[state]; ok {
+		return
+	}
+
+	if !c.defined(state) {
+		c.add(fmt.Errorf("%w: %q for %s", ErrStateNotFound, state, at))
+	}
+}
+
+// err joins all the problems found.
+func (c *programCheck) err() error {
+	return errors.Join(c.errs...)
+}
+
+// validateStart returns err, the result of validating program, joined after the problem
+// of the start state if it is neither defined in program nor one of the halting states.
+func validateStart[V any](program map[string]V, start string, haltingStates []string, err error) error {
+	if _, ok := program[start]; ok || slices.Contains(haltingStates, start) {
+		return err
+	}
+
+	return errors.Join(fmt.Errorf("%w: start state %q", ErrStateNotFound, start), err)
+}
+
+// isMove reports whether a one-dimensional machine accepts the move.
+func isMove(move Direction) bool {
+	return move == Left || move == Right || move == Stay
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}